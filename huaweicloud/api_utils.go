@@ -0,0 +1,162 @@
+package huaweicloud
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/huaweicloud/golangsdk"
+)
+
+// apiOkCodes are the response codes of successful API requests.
+var apiOkCodes = []int{200, 201, 202, 203, 204}
+
+// apiResponse is the response of an API request sent by the api_request data
+// source or the api_resource resource.
+type apiResponse struct {
+	StatusCode int
+	// Body is the raw response body, Result the decoded JSON body if any.
+	Body   string
+	Result interface{}
+}
+
+// apiServiceClientFromSchema returns the client of the service_type argument.
+func apiServiceClientFromSchema(d *schema.ResourceData, config *Config) (*golangsdk.ServiceClient, error) {
+	serviceType := d.Get("service_type").(string)
+	client, err := config.apiServiceClient(serviceType, GetRegion(d, config))
+	if err != nil {
+		return nil, fmt.Errorf("Error creating HuaweiCloud %s client: %s", serviceType, err)
+	}
+	return client, nil
+}
+
+// expandAPIPath replaces the {project_id}, {domain_id}, {region} and {id}
+// placeholders of a path template and returns its URL. The ID is escaped, it
+// may hold any character. A path starting with a slash is relative to the host
+// of the endpoint of the service, other paths to the endpoint itself.
+func expandAPIPath(client *golangsdk.ServiceClient, config *Config, path, region, id string) (string, error) {
+	var domainID string
+	if strings.Contains(path, "{domain_id}") {
+		var err error
+		if domainID, err = config.domainID(); err != nil {
+			return "", fmt.Errorf("Error expanding the {domain_id} placeholder of %s: %s", path, err)
+		}
+	}
+
+	path = strings.NewReplacer(
+		"{project_id}", client.ProjectID,
+		"{domain_id}", domainID,
+		"{region}", region,
+		"{id}", url.PathEscape(id),
+	).Replace(path)
+
+	if !strings.HasPrefix(path, "/") {
+		return client.Endpoint + path, nil
+	}
+
+	endpoint, err := url.Parse(client.Endpoint)
+	if err != nil {
+		return "", fmt.Errorf("Error parsing endpoint %s: %s", client.Endpoint, err)
+	}
+	return endpoint.Scheme + "://" + endpoint.Host + path, nil
+}
+
+// sendAPIRequest sends a request with the given JSON body, if any, and
+// returns its response.
+func sendAPIRequest(client *golangsdk.ServiceClient, method, url, body string, headers map[string]interface{}) (*apiResponse, error) {
+	opts := &golangsdk.RequestOpts{
+		OkCodes:     apiOkCodes,
+		MoreHeaders: make(map[string]string),
+	}
+	if body != "" {
+		var jsonBody interface{}
+		if err := json.Unmarshal([]byte(body), &jsonBody); err != nil {
+			return nil, fmt.Errorf("Error decoding the request body: %s", err)
+		}
+		opts.JSONBody = jsonBody
+	}
+	for k, v := range headers {
+		opts.MoreHeaders[k] = v.(string)
+	}
+
+	log.Printf("[DEBUG] Sending API request %s %s", method, url)
+	resp, err := client.Request(method, url, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	raw, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading the response of %s %s: %s", method, url, err)
+	}
+
+	result := &apiResponse{
+		StatusCode: resp.StatusCode,
+		Body:       string(raw),
+	}
+	if len(strings.TrimSpace(result.Body)) > 0 {
+		// Bodies which aren't JSON are only exported raw.
+		if err := json.Unmarshal(raw, &result.Result); err != nil {
+			log.Printf("[DEBUG] The response of %s %s isn't JSON: %s", method, url, err)
+		}
+	}
+
+	return result, nil
+}
+
+// jsonPathValue returns the value at a path of dot separated object keys and
+// list indexes in a decoded JSON document, e.g. "servers.0.id".
+func jsonPathValue(doc interface{}, path string) (interface{}, bool) {
+	if path == "" {
+		return doc, true
+	}
+
+	v := doc
+	for _, key := range strings.Split(path, ".") {
+		switch node := v.(type) {
+		case map[string]interface{}:
+			child, ok := node[key]
+			if !ok {
+				return nil, false
+			}
+			v = child
+		case []interface{}:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil, false
+			}
+			v = node[i]
+		default:
+			return nil, false
+		}
+	}
+
+	return v, true
+}
+
+// jsonValueString returns a string as is and any other JSON value encoded.
+func jsonValueString(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+// flattenAPIResults returns the values at the paths of the result_paths
+// argument in the response, keyed like the paths. Missing values are left out.
+func flattenAPIResults(d *schema.ResourceData, resp *apiResponse) map[string]string {
+	results := make(map[string]string)
+	for name, path := range d.Get("result_paths").(map[string]interface{}) {
+		if v, ok := jsonPathValue(resp.Result, path.(string)); ok && v != nil {
+			results[name] = jsonValueString(v)
+		}
+	}
+	return results
+}
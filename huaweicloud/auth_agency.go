@@ -0,0 +1,105 @@
+package huaweicloud
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/huaweicloud/golangsdk"
+	huaweisdk "github.com/huaweicloud/golangsdk/openstack"
+)
+
+// AssumeRole holds the settings of the assume_role provider block.
+type AssumeRole struct {
+	AgencyName string
+	DomainName string
+	DomainID   string
+	Duration   int
+}
+
+// AgencyCredentials obtains temporary credentials for an IAM agency of another
+// account, using the base credentials of the provider, and renews them before
+// they expire.
+type AgencyCredentials struct {
+	temporaryCredentials
+
+	// Client is an identity client authenticated with the base credentials.
+	Client     *golangsdk.ServiceClient
+	AssumeRole AssumeRole
+}
+
+// Get returns the current credentials of the agency, assuming the agency again
+// if they are missing or about to expire.
+func (a *AgencyCredentials) Get() (akskCredentials, error) {
+	return a.get(a.fetch)
+}
+
+func (a *AgencyCredentials) fetch() (akskCredentials, time.Time, error) {
+	assumeRole := map[string]interface{}{
+		"agency_name": a.AssumeRole.AgencyName,
+	}
+	if a.AssumeRole.DomainID != "" {
+		assumeRole["domain_id"] = a.AssumeRole.DomainID
+	} else {
+		assumeRole["domain_name"] = a.AssumeRole.DomainName
+	}
+	if a.AssumeRole.Duration > 0 {
+		assumeRole["duration_seconds"] = a.AssumeRole.Duration
+	}
+
+	body := map[string]interface{}{
+		"auth": map[string]interface{}{
+			"identity": map[string]interface{}{
+				"methods":     []string{"assume_role"},
+				"assume_role": assumeRole,
+			},
+		},
+	}
+
+	url := a.Client.IdentityBase + "v3.0/OS-CREDENTIAL/securitytokens"
+	log.Printf("[DEBUG] Assuming agency %s through %s", a.AssumeRole.AgencyName, url)
+
+	var result temporaryCredentialsResult
+	_, err := a.Client.Post(url, body, &result, &golangsdk.RequestOpts{
+		OkCodes: []int{201},
+	})
+	if err != nil {
+		return akskCredentials{}, time.Time{}, err
+	}
+
+	return result.Extract()
+}
+
+// loadAgencyCredentials authenticates with the base credentials of the Config
+// and switches the Config to the temporary credentials of the agency. The
+// domain and project are then those of the account owning the agency.
+func (c *Config) loadAgencyCredentials() error {
+	base := *c
+	base.AssumeRole = nil
+	if err := newhwClient(&base); err != nil {
+		return err
+	}
+
+	identity, err := huaweisdk.NewIdentityV3(base.HwClient, golangsdk.EndpointOpts{})
+	if err != nil {
+		return err
+	}
+
+	creds := &AgencyCredentials{
+		Client:     identity,
+		AssumeRole: *c.AssumeRole,
+	}
+	if _, err := creds.Get(); err != nil {
+		return fmt.Errorf("Error assuming agency %s: %s", c.AssumeRole.AgencyName, err)
+	}
+
+	log.Printf("[INFO] Using the temporary credentials of agency %s", c.AssumeRole.AgencyName)
+	c.credsSource = creds
+	c.DomainID = c.AssumeRole.DomainID
+	c.DomainName = c.AssumeRole.DomainName
+	// The project of the base credentials doesn't exist in the other account,
+	// it is looked up by name instead.
+	c.TenantID = ""
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package huaweicloud
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/huaweicloud/golangsdk"
+)
+
+func TestAgencyCredentials_get(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/v3.0/OS-CREDENTIAL/securitytokens" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.Header.Get("X-Auth-Token") != "base-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		var body struct {
+			Auth struct {
+				Identity struct {
+					Methods    []string               `json:"methods"`
+					AssumeRole map[string]interface{} `json:"assume_role"`
+				} `json:"identity"`
+			} `json:"auth"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		role := body.Auth.Identity.AssumeRole
+		if role["agency_name"] != "admin" || role["domain_name"] != "tenant" || role["duration_seconds"] != 3600.0 {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, "unexpected assume_role: %v", role)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"credential":{"access":"AK","secret":"SK","securitytoken":"token","expires_at":"2099-01-01T00:00:00.000000Z"}}`)
+	}))
+	defer server.Close()
+
+	provider := &golangsdk.ProviderClient{
+		IdentityBase: server.URL + "/",
+		TokenID:      "base-token",
+	}
+	creds := &AgencyCredentials{
+		Client: &golangsdk.ServiceClient{ProviderClient: provider},
+		AssumeRole: AssumeRole{
+			AgencyName: "admin",
+			DomainName: "tenant",
+			Duration:   3600,
+		},
+	}
+
+	v, err := creds.Get()
+	if err != nil {
+		t.Fatalf("Error assuming agency: %s", err)
+	}
+	if v.AccessKey != "AK" || v.SecretKey != "SK" || v.SecurityToken != "token" {
+		t.Fatalf("Unexpected credentials: %#v", v)
+	}
+}
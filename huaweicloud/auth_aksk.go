@@ -0,0 +1,382 @@
+package huaweicloud
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/huaweicloud/golangsdk"
+	huaweisdk "github.com/huaweicloud/golangsdk/openstack"
+	hwtokens3 "github.com/huaweicloud/golangsdk/openstack/identity/v3/tokens"
+)
+
+const (
+	akskAlgorithm  = "SDK-HMAC-SHA256"
+	akskDateFormat = "20060102T150405Z"
+	akskDateHeader = "X-Sdk-Date"
+)
+
+// AKSKRoundTripper satisfies the http.RoundTripper interface and signs every
+// request with the HuaweiCloud AK/SK signature before passing it on to the
+// wrapped RoundTripper. SecurityToken is only set for temporary credentials.
+// If Credentials is set, the temporary keys are taken from it instead.
+type AKSKRoundTripper struct {
+	Rt            http.RoundTripper
+	AccessKey     string
+	SecretKey     string
+	SecurityToken string
+	Credentials   akskCredentialsSource
+	ProjectID     string
+	DomainID      string
+}
+
+// ErrCredentialsExpired is returned when a request signed with temporary
+// security credentials is rejected as unauthorized.
+type ErrCredentialsExpired struct {
+	URL  string
+	Body []byte
+}
+
+func (e ErrCredentialsExpired) Error() string {
+	return fmt.Sprintf("The temporary security credentials were rejected by %s, "+
+		"they have most likely expired and must be renewed: %s", e.URL, e.Body)
+}
+
+// RoundTrip signs the request and performs the round-trip.
+func (srt *AKSKRoundTripper) RoundTrip(request *http.Request) (*http.Response, error) {
+	creds := akskCredentials{
+		AccessKey:     srt.AccessKey,
+		SecretKey:     srt.SecretKey,
+		SecurityToken: srt.SecurityToken,
+	}
+	if srt.Credentials != nil {
+		var err error
+		creds, err = srt.Credentials.Get()
+		if err != nil {
+			return nil, fmt.Errorf("Error fetching temporary credentials: %s", err)
+		}
+	}
+
+	var body []byte
+	if request.Body != nil {
+		var err error
+		body, err = ioutil.ReadAll(request.Body)
+		request.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		request.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+
+	// Don't modify the caller's request, http.RoundTripper forbids it.
+	signed := new(http.Request)
+	*signed = *request
+	signed.Header = make(http.Header, len(request.Header))
+	for k, v := range request.Header {
+		signed.Header[k] = append([]string(nil), v...)
+	}
+
+	// Tokens are meaningless in AK/SK mode and would be part of the signature.
+	signed.Header.Del("X-Auth-Token")
+	if srt.ProjectID != "" && signed.Header.Get("X-Project-Id") == "" {
+		signed.Header.Set("X-Project-Id", srt.ProjectID)
+	}
+	if srt.ProjectID == "" && srt.DomainID != "" && signed.Header.Get("X-Domain-Id") == "" {
+		signed.Header.Set("X-Domain-Id", srt.DomainID)
+	}
+	if creds.SecurityToken != "" {
+		signed.Header.Set("X-Security-Token", creds.SecurityToken)
+	}
+
+	signAKSKRequest(signed, body, creds.AccessKey, creds.SecretKey, time.Now())
+
+	response, err := srt.Rt.RoundTrip(signed)
+	if err != nil || srt.Credentials != nil || srt.SecurityToken == "" ||
+		response.StatusCode != http.StatusUnauthorized {
+		return response, err
+	}
+
+	// Temporary credentials set in the configuration can't be renewed, so
+	// report the expiry clearly instead of a bare 401.
+	defer response.Body.Close()
+	respBody, _ := ioutil.ReadAll(response.Body)
+	return nil, ErrCredentialsExpired{URL: request.URL.String(), Body: respBody}
+}
+
+// signAKSKRequest adds the X-Sdk-Date and Authorization headers computed with
+// the SDK-HMAC-SHA256 algorithm to the request.
+func signAKSKRequest(r *http.Request, body []byte, accessKey, secretKey string, t time.Time) {
+	if r.Header.Get(akskDateHeader) == "" {
+		r.Header.Set(akskDateHeader, t.UTC().Format(akskDateFormat))
+	}
+
+	host := r.Host
+	if host == "" {
+		host = r.URL.Host
+	}
+	r.Header.Set("Host", host)
+
+	signedHeaders := akskSignedHeaders(r)
+	canonical := strings.Join([]string{
+		r.Method,
+		akskCanonicalURI(r.URL),
+		akskCanonicalQueryString(r.URL),
+		akskCanonicalHeaders(r, signedHeaders, host),
+		strings.Join(signedHeaders, ";"),
+		akskHexSHA256(body),
+	}, "\n")
+
+	stringToSign := strings.Join([]string{
+		akskAlgorithm,
+		r.Header.Get(akskDateHeader),
+		akskHexSHA256([]byte(canonical)),
+	}, "\n")
+
+	mac := hmac.New(sha256.New, []byte(secretKey))
+	mac.Write([]byte(stringToSign))
+	signature := hex.EncodeToString(mac.Sum(nil))
+
+	r.Header.Set("Authorization", fmt.Sprintf("%s Access=%s, SignedHeaders=%s, Signature=%s",
+		akskAlgorithm, accessKey, strings.Join(signedHeaders, ";"), signature))
+
+	// Host is taken from the request itself by net/http.
+	r.Header.Del("Host")
+}
+
+func akskHexSHA256(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// akskEscape percent-encodes everything but the unreserved characters of
+// RFC 3986.
+func akskEscape(s string) string {
+	var b bytes.Buffer
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if ('A' <= c && c <= 'Z') || ('a' <= c && c <= 'z') || ('0' <= c && c <= '9') ||
+			c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func akskCanonicalURI(u *url.URL) string {
+	segments := strings.Split(u.Path, "/")
+	for i, s := range segments {
+		segments[i] = akskEscape(s)
+	}
+
+	uri := strings.Join(segments, "/")
+	if !strings.HasSuffix(uri, "/") {
+		uri = uri + "/"
+	}
+	return uri
+}
+
+func akskCanonicalQueryString(u *url.URL) string {
+	query := u.Query()
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var pairs []string
+	for _, k := range keys {
+		values := query[k]
+		sort.Strings(values)
+		for _, v := range values {
+			pairs = append(pairs, akskEscape(k)+"="+akskEscape(v))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+func akskSignedHeaders(r *http.Request) []string {
+	headers := make([]string, 0, len(r.Header))
+	for k := range r.Header {
+		headers = append(headers, strings.ToLower(k))
+	}
+	sort.Strings(headers)
+	return headers
+}
+
+func akskCanonicalHeaders(r *http.Request, signedHeaders []string, host string) string {
+	lower := make(map[string][]string, len(r.Header))
+	for k, v := range r.Header {
+		lower[strings.ToLower(k)] = v
+	}
+
+	var lines []string
+	for _, k := range signedHeaders {
+		values := lower[k]
+		if k == "host" {
+			values = []string{host}
+		}
+		sort.Strings(values)
+		for _, v := range values {
+			lines = append(lines, k+":"+strings.TrimSpace(v))
+		}
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// akskScope holds the project, domain and service catalog resolved for an
+// AK/SK authenticated session.
+type akskScope struct {
+	ProjectID string
+	DomainID  string
+	Catalog   []byte
+}
+
+// usingAKSK returns true when the Config should sign requests with AK/SK
+// instead of obtaining a token from IAM.
+func (c *Config) usingAKSK() bool {
+	if c.credsSource != nil {
+		return true
+	}
+	return c.AccessKey != "" && c.SecretKey != "" &&
+		c.Password == "" && c.Token == "" && !c.Swauth
+}
+
+// akskGetJSON sends a signed GET request to the identity service and decodes
+// the JSON response into out.
+func akskGetJSON(client *http.Client, userAgent, rawURL string, out interface{}) error {
+	req, err := http.NewRequest("GET", rawURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Unexpected response code %d from %s: %s", resp.StatusCode, rawURL, body)
+	}
+
+	return json.Unmarshal(body, out)
+}
+
+// resolveAKSKScope looks up the domain and project the AK/SK belong to along
+// with the service catalog of that project. The result is cached on the Config
+// so it's only resolved once.
+func (c *Config) resolveAKSKScope(signer *AKSKRoundTripper, client *http.Client, userAgent, identityURL string) (*akskScope, error) {
+	if c.akskScope != nil {
+		signer.ProjectID = c.akskScope.ProjectID
+		signer.DomainID = c.akskScope.DomainID
+		return c.akskScope, nil
+	}
+
+	scope := &akskScope{
+		ProjectID: c.TenantID,
+		DomainID:  c.DomainID,
+	}
+
+	if scope.DomainID == "" {
+		var domains struct {
+			Domains []struct {
+				ID   string `json:"id"`
+				Name string `json:"name"`
+			} `json:"domains"`
+		}
+		if err := akskGetJSON(client, userAgent, identityURL+"auth/domains", &domains); err != nil {
+			return nil, fmt.Errorf("Error fetching the domain of the access key: %s", err)
+		}
+		for _, d := range domains.Domains {
+			if c.DomainName == "" || d.Name == c.DomainName {
+				scope.DomainID = d.ID
+				break
+			}
+		}
+		if scope.DomainID == "" {
+			return nil, fmt.Errorf("Unable to find the domain of the access key")
+		}
+	}
+	signer.DomainID = scope.DomainID
+
+	if scope.ProjectID == "" {
+		name := c.TenantName
+		if name == "" {
+			name = c.Region
+		}
+		if name == "" {
+			return nil, fmt.Errorf("One of tenant_id, tenant_name or region must be set when using access_key and secret_key")
+		}
+
+		var projects struct {
+			Projects []struct {
+				ID string `json:"id"`
+			} `json:"projects"`
+		}
+		query := url.Values{"domain_id": {scope.DomainID}, "name": {name}}
+		if err := akskGetJSON(client, userAgent, identityURL+"projects?"+query.Encode(), &projects); err != nil {
+			return nil, fmt.Errorf("Error fetching project %q: %s", name, err)
+		}
+		if len(projects.Projects) != 1 {
+			return nil, fmt.Errorf("Expected exactly one project named %q, got %d", name, len(projects.Projects))
+		}
+		scope.ProjectID = projects.Projects[0].ID
+	}
+	signer.ProjectID = scope.ProjectID
+
+	var catalog json.RawMessage
+	if err := akskGetJSON(client, userAgent, identityURL+"auth/catalog", &catalog); err != nil {
+		return nil, fmt.Errorf("Error fetching the service catalog: %s", err)
+	}
+	scope.Catalog = catalog
+
+	log.Printf("[DEBUG] Using AK/SK authentication for project %s in domain %s", scope.ProjectID, scope.DomainID)
+	c.akskScope = scope
+
+	return scope, nil
+}
+
+// akskAuthenticateHw configures a golangsdk ProviderClient whose transport
+// is an AKSKRoundTripper to use the resolved service catalog.
+func akskAuthenticateHw(c *Config, client *golangsdk.ProviderClient, signer *AKSKRoundTripper) error {
+	identity, err := huaweisdk.NewIdentityV3(client, golangsdk.EndpointOpts{})
+	if err != nil {
+		return err
+	}
+
+	scope, err := c.resolveAKSKScope(signer, &client.HTTPClient, client.UserAgent.Join(), identity.Endpoint)
+	if err != nil {
+		return err
+	}
+
+	var catalog hwtokens3.ServiceCatalog
+	if err := json.Unmarshal(scope.Catalog, &catalog); err != nil {
+		return fmt.Errorf("Error parsing the service catalog: %s", err)
+	}
+
+	client.ProjectID = scope.ProjectID
+	client.EndpointLocator = func(opts golangsdk.EndpointOpts) (string, error) {
+		return huaweisdk.V3EndpointURL(&catalog, opts)
+	}
+
+	return nil
+}
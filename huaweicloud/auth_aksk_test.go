@@ -0,0 +1,150 @@
+package huaweicloud
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAKSKCanonicalRequest(t *testing.T) {
+	u, _ := url.Parse("https://ecs.example.com/v2/abc/servers?name=a b&limit=10&limit=2")
+
+	if v := akskCanonicalURI(u); v != "/v2/abc/servers/" {
+		t.Fatalf("Unexpected canonical URI: %s", v)
+	}
+
+	if v := akskCanonicalQueryString(u); v != "limit=10&limit=2&name=a%20b" {
+		t.Fatalf("Unexpected canonical query string: %s", v)
+	}
+}
+
+func TestAKSKSignRequest(t *testing.T) {
+	now := time.Date(2018, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	sign := func(body string) string {
+		req, _ := http.NewRequest("POST", "https://ecs.example.com/v2/abc/servers", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		signAKSKRequest(req, []byte(body), "AK", "SK", now)
+
+		if v := req.Header.Get(akskDateHeader); v != "20180501T100000Z" {
+			t.Fatalf("Unexpected %s header: %s", akskDateHeader, v)
+		}
+		if v := req.Header.Get("Host"); v != "" {
+			t.Fatalf("Host header should not be left on the request: %s", v)
+		}
+		return req.Header.Get("Authorization")
+	}
+
+	auth := sign(`{"server":{}}`)
+	prefix := "SDK-HMAC-SHA256 Access=AK, SignedHeaders=content-type;host;x-sdk-date, Signature="
+	if !strings.HasPrefix(auth, prefix) {
+		t.Fatalf("Unexpected Authorization header: %s", auth)
+	}
+
+	if auth == sign(`{"server":{"name":"x"}}`) {
+		t.Fatal("Expected the signature to depend on the request body")
+	}
+}
+
+func TestAKSKResolveScope(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), akskAlgorithm) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/v3/auth/domains":
+			fmt.Fprint(w, `{"domains":[{"id":"domain-1","name":"example"}]}`)
+		case "/v3/projects":
+			if r.URL.Query().Get("name") != "cn-north-1" || r.Header.Get("X-Domain-Id") != "domain-1" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			fmt.Fprint(w, `{"projects":[{"id":"project-1"}]}`)
+		case "/v3/auth/catalog":
+			if r.Header.Get("X-Project-Id") != "project-1" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			fmt.Fprint(w, `{"catalog":[]}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	config := Config{
+		AccessKey: "AK",
+		SecretKey: "SK",
+		Region:    "cn-north-1",
+	}
+	signer := &AKSKRoundTripper{
+		Rt:        http.DefaultTransport,
+		AccessKey: config.AccessKey,
+		SecretKey: config.SecretKey,
+	}
+	client := &http.Client{Transport: signer}
+
+	scope, err := config.resolveAKSKScope(signer, client, "test", server.URL+"/v3/")
+	if err != nil {
+		t.Fatalf("Error resolving AK/SK scope: %s", err)
+	}
+
+	if scope.DomainID != "domain-1" || scope.ProjectID != "project-1" {
+		t.Fatalf("Unexpected scope: %#v", scope)
+	}
+	if signer.ProjectID != "project-1" {
+		t.Fatalf("Expected the signer to use project-1, got %s", signer.ProjectID)
+	}
+}
+
+func TestAKSKSecurityToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Security-Token") != "token" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if !strings.Contains(r.Header.Get("Authorization"), "x-security-token") {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if r.URL.Path == "/expired" {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"error_msg":"The security token is expired"}`)
+			return
+		}
+		fmt.Fprint(w, `{}`)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: &AKSKRoundTripper{
+		Rt:            http.DefaultTransport,
+		AccessKey:     "AK",
+		SecretKey:     "SK",
+		SecurityToken: "token",
+	}}
+
+	resp, err := client.Get(server.URL + "/ok")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", resp.StatusCode)
+	}
+
+	_, err = client.Get(server.URL + "/expired")
+	if err == nil {
+		t.Fatal("Expected an error for expired credentials")
+	}
+	if uerr, ok := err.(*url.Error); !ok {
+		t.Fatalf("Expected a *url.Error, got %T", err)
+	} else if _, ok := uerr.Err.(ErrCredentialsExpired); !ok {
+		t.Fatalf("Expected ErrCredentialsExpired, got %T: %s", uerr.Err, uerr.Err)
+	}
+}
@@ -1,94 +1,172 @@
 package huaweicloud
 
 import (
+	"fmt"
 	"log"
 	"os"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	awsCredentials "github.com/aws/aws-sdk-go/aws/credentials"
-	"github.com/aws/aws-sdk-go/aws/credentials/ec2rolecreds"
 	"github.com/aws/aws-sdk-go/aws/defaults"
-	"github.com/aws/aws-sdk-go/aws/ec2metadata"
-	"github.com/aws/aws-sdk-go/aws/session"
 	cleanhttp "github.com/hashicorp/go-cleanhttp"
 )
 
 func GetCredentials(c *Config) (*awsCredentials.Credentials, error) {
 	// build a chain provider, lazy-evaluated by aws-sdk
-	providers := []awsCredentials.Provider{
+	providers := []awsCredentials.Provider{}
+
+	// Temporary credentials of an agency take precedence over the static ones,
+	// which may only be those used to assume the agency.
+	if c.credsSource != nil {
+		providers = append(providers, &temporaryCredentialsProvider{creds: c.credsSource})
+		log.Print("[INFO] Temporary credentials added to the auth chain")
+	}
+
+	providers = append(providers,
 		&awsCredentials.StaticProvider{Value: awsCredentials.Value{
 			AccessKeyID:     c.AccessKey,
 			SecretAccessKey: c.SecretKey,
-			SessionToken:    c.Token,
+			SessionToken:    c.SecurityToken,
 		}},
 		&awsCredentials.EnvProvider{},
 		&awsCredentials.SharedCredentialsProvider{
 			Filename: "",
 			Profile:  "",
 		},
-	}
+	)
 
-	// Build isolated HTTP client to avoid issues with globally-shared settings
-	client := cleanhttp.DefaultClient()
-
-	// Keep the default timeout (100ms) low as we don't want to wait in non-EC2 environments
-	client.Timeout = 100 * time.Millisecond
-
-	const userTimeoutEnvVar = "AWS_METADATA_TIMEOUT"
-	userTimeout := os.Getenv(userTimeoutEnvVar)
-	if userTimeout != "" {
-		newTimeout, err := time.ParseDuration(userTimeout)
-		if err == nil {
-			if newTimeout.Nanoseconds() > 0 {
-				client.Timeout = newTimeout
-			} else {
-				log.Printf("[WARN] Non-positive value of %s (%s) is meaningless, ignoring", userTimeoutEnvVar, newTimeout.String())
-			}
-		} else {
-			log.Printf("[WARN] Error converting %s to time.Duration: %s", userTimeoutEnvVar, err)
+	// Add the default AWS provider for ECS Task Roles if the relevant env variable is set
+	if uri := os.Getenv("AWS_CONTAINER_CREDENTIALS_RELATIVE_URI"); len(uri) > 0 {
+		cfg := &aws.Config{
+			HTTPClient: cleanhttp.DefaultClient(),
 		}
+		providers = append(providers, defaults.RemoteCredProvider(*cfg, defaults.Handlers()))
+		log.Print("[INFO] ECS container credentials detected, RemoteCredProvider added to auth chain")
 	}
 
-	log.Printf("[INFO] Setting AWS metadata API timeout to %s", client.Timeout.String())
-	cfg := &aws.Config{
-		HTTPClient: client,
+	return awsCredentials.NewChainCredentials(providers), nil
+}
+
+// Temporary credentials are renewed this long before they expire so that no
+// request is signed with credentials that expire while in flight.
+const temporaryCredentialsExpiryWindow = 5 * time.Minute
+
+// akskCredentials is a set of access key, secret key and, for temporary
+// credentials, security token.
+type akskCredentials struct {
+	AccessKey     string
+	SecretKey     string
+	SecurityToken string
+}
+
+// akskCredentialsSource provides temporary credentials which are renewed
+// before they expire.
+type akskCredentialsSource interface {
+	Get() (akskCredentials, error)
+	ExpiresAt() time.Time
+	Expire()
+}
+
+// temporaryCredentials caches temporary credentials until they are about to
+// expire. It's meant to be embedded by akskCredentialsSource implementations.
+type temporaryCredentials struct {
+	mu        sync.Mutex
+	value     akskCredentials
+	expiresAt time.Time
+}
+
+// get returns the cached credentials, renewing them with fetch if they are
+// missing or about to expire.
+func (t *temporaryCredentials) get(fetch func() (akskCredentials, time.Time, error)) (akskCredentials, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.value.AccessKey != "" && time.Now().Add(temporaryCredentialsExpiryWindow).Before(t.expiresAt) {
+		return t.value, nil
 	}
-	usedEndpoint := setOptionalEndpoint(cfg)
 
-	// Add the default AWS provider for ECS Task Roles if the relevant env variable is set
-	if uri := os.Getenv("AWS_CONTAINER_CREDENTIALS_RELATIVE_URI"); len(uri) > 0 {
-		providers = append(providers, defaults.RemoteCredProvider(*cfg, defaults.Handlers()))
-		log.Print("[INFO] ECS container credentials detected, RemoteCredProvider added to auth chain")
+	value, expiresAt, err := fetch()
+	if err != nil {
+		return akskCredentials{}, err
 	}
+	log.Printf("[DEBUG] Fetched temporary credentials expiring at %s", expiresAt)
 
-	// Real AWS should reply to a simple metadata request.
-	// We check it actually does to ensure something else didn't just
-	// happen to be listening on the same IP:Port
-	metadataClient := ec2metadata.New(session.New(cfg))
-	if metadataClient.Available() {
-		providers = append(providers, &ec2rolecreds.EC2RoleProvider{
-			Client: metadataClient,
-		})
-		log.Print("[INFO] AWS EC2 instance detected via default metadata" +
-			" API endpoint, EC2RoleProvider added to the auth chain")
-	} else {
-		if usedEndpoint == "" {
-			usedEndpoint = "default location"
-		}
-		log.Printf("[INFO] Ignoring AWS metadata API endpoint at %s "+
-			"as it doesn't return any instance-id", usedEndpoint)
+	t.value = value
+	t.expiresAt = expiresAt
+
+	return t.value, nil
+}
+
+// ExpiresAt returns the expiry time of the credentials last fetched.
+func (t *temporaryCredentials) ExpiresAt() time.Time {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.expiresAt
+}
+
+// Expire forces the credentials to be renewed on the next call to get.
+func (t *temporaryCredentials) Expire() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.expiresAt = time.Time{}
+}
+
+// temporaryCredentialsResult is the response body of the APIs returning
+// temporary credentials.
+type temporaryCredentialsResult struct {
+	Credential struct {
+		Access        string `json:"access"`
+		Secret        string `json:"secret"`
+		SecurityToken string `json:"securitytoken"`
+		ExpiresAt     string `json:"expires_at"`
+	} `json:"credential"`
+}
+
+// Extract returns the credentials and their expiry time.
+func (r temporaryCredentialsResult) Extract() (akskCredentials, time.Time, error) {
+	cred := r.Credential
+	if cred.Access == "" || cred.Secret == "" {
+		return akskCredentials{}, time.Time{}, fmt.Errorf("No temporary credentials were returned")
 	}
 
-	return awsCredentials.NewChainCredentials(providers), nil
+	expiresAt, err := time.Parse(time.RFC3339Nano, cred.ExpiresAt)
+	if err != nil {
+		return akskCredentials{}, time.Time{}, fmt.Errorf("Error parsing the expiry time %q: %s", cred.ExpiresAt, err)
+	}
+
+	return akskCredentials{
+		AccessKey:     cred.Access,
+		SecretKey:     cred.Secret,
+		SecurityToken: cred.SecurityToken,
+	}, expiresAt, nil
 }
 
-func setOptionalEndpoint(cfg *aws.Config) string {
-	endpoint := os.Getenv("AWS_METADATA_URL")
-	if endpoint != "" {
-		log.Printf("[INFO] Setting custom metadata endpoint: %q", endpoint)
-		cfg.Endpoint = aws.String(endpoint)
-		return endpoint
+// temporaryCredentialsProvider makes an akskCredentialsSource usable by the
+// aws-sdk credential chain.
+type temporaryCredentialsProvider struct {
+	creds akskCredentialsSource
+}
+
+// Retrieve satisfies the awsCredentials.Provider interface.
+func (p *temporaryCredentialsProvider) Retrieve() (awsCredentials.Value, error) {
+	v, err := p.creds.Get()
+	if err != nil {
+		return awsCredentials.Value{}, err
 	}
-	return ""
+
+	return awsCredentials.Value{
+		AccessKeyID:     v.AccessKey,
+		SecretAccessKey: v.SecretKey,
+		SessionToken:    v.SecurityToken,
+		ProviderName:    "HuaweiCloudTemporaryCredentialsProvider",
+	}, nil
+}
+
+// IsExpired satisfies the awsCredentials.Provider interface.
+func (p *temporaryCredentialsProvider) IsExpired() bool {
+	return !time.Now().Add(temporaryCredentialsExpiryWindow).Before(p.creds.ExpiresAt())
 }
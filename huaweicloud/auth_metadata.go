@@ -0,0 +1,115 @@
+package huaweicloud
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	cleanhttp "github.com/hashicorp/go-cleanhttp"
+)
+
+const (
+	defaultMetadataEndpoint = "http://169.254.169.254"
+	metadataSecurityKeyPath = "/openstack/latest/securitykey"
+)
+
+// MetadataCredentials fetches the temporary credentials of the agency attached
+// to an ECS instance from the metadata API, and refreshes them before they
+// expire.
+type MetadataCredentials struct {
+	temporaryCredentials
+
+	Endpoint string
+	Client   *http.Client
+}
+
+// newMetadataCredentials returns a MetadataCredentials for the default metadata
+// endpoint. OS_METADATA_URL and OS_METADATA_TIMEOUT may be used to override the
+// endpoint and timeout.
+func newMetadataCredentials() *MetadataCredentials {
+	endpoint := defaultMetadataEndpoint
+	if v := os.Getenv("OS_METADATA_URL"); v != "" {
+		log.Printf("[INFO] Setting custom metadata endpoint: %q", v)
+		endpoint = v
+	}
+
+	// Build isolated HTTP client to avoid issues with globally-shared settings
+	client := cleanhttp.DefaultClient()
+
+	// Keep the default timeout low as we don't want to wait in non-ECS environments
+	client.Timeout = 1 * time.Second
+
+	const userTimeoutEnvVar = "OS_METADATA_TIMEOUT"
+	userTimeout := os.Getenv(userTimeoutEnvVar)
+	if userTimeout != "" {
+		newTimeout, err := time.ParseDuration(userTimeout)
+		if err == nil {
+			if newTimeout.Nanoseconds() > 0 {
+				client.Timeout = newTimeout
+			} else {
+				log.Printf("[WARN] Non-positive value of %s (%s) is meaningless, ignoring", userTimeoutEnvVar, newTimeout.String())
+			}
+		} else {
+			log.Printf("[WARN] Error converting %s to time.Duration: %s", userTimeoutEnvVar, err)
+		}
+	}
+
+	return &MetadataCredentials{
+		Endpoint: strings.TrimSuffix(endpoint, "/"),
+		Client:   client,
+	}
+}
+
+// Get returns the current credentials, fetching new ones from the metadata API
+// if they are missing or about to expire.
+func (m *MetadataCredentials) Get() (akskCredentials, error) {
+	return m.get(m.fetch)
+}
+
+func (m *MetadataCredentials) fetch() (akskCredentials, time.Time, error) {
+	url := m.Endpoint + metadataSecurityKeyPath
+	log.Printf("[DEBUG] Fetching temporary credentials from %s", url)
+
+	resp, err := m.Client.Get(url)
+	if err != nil {
+		return akskCredentials{}, time.Time{}, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return akskCredentials{}, time.Time{}, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return akskCredentials{}, time.Time{}, fmt.Errorf("Unexpected response code %d from %s", resp.StatusCode, url)
+	}
+
+	var result temporaryCredentialsResult
+	if err := json.Unmarshal(body, &result); err != nil {
+		return akskCredentials{}, time.Time{}, fmt.Errorf("Error parsing the response from %s: %s", url, err)
+	}
+	if result.Credential.Access == "" {
+		return akskCredentials{}, time.Time{}, fmt.Errorf(
+			"No credentials returned by %s, is an agency attached to the instance?", url)
+	}
+
+	return result.Extract()
+}
+
+// loadMetadataCredentials configures the Config to use the credentials of the
+// ECS instance agency, if Terraform runs on an ECS instance with an agency.
+func (c *Config) loadMetadataCredentials() {
+	creds := newMetadataCredentials()
+	if _, err := creds.Get(); err != nil {
+		log.Printf("[INFO] Ignoring HuaweiCloud metadata API endpoint at %s: %s", creds.Endpoint, err)
+		return
+	}
+
+	log.Print("[INFO] ECS instance agency detected via metadata API, using its temporary credentials")
+	c.credsSource = creds
+}
@@ -0,0 +1,95 @@
+package huaweicloud
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+func testMetadataServer(validity time.Duration, calls *int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != metadataSecurityKeyPath {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		*calls++
+		fmt.Fprintf(w, `{"credential":{"access":"AK%d","secret":"SK","securitytoken":"token","expires_at":"%s"}}`,
+			*calls, time.Now().Add(validity).UTC().Format("2006-01-02T15:04:05.000000Z"))
+	}))
+}
+
+func TestMetadataCredentials_refresh(t *testing.T) {
+	var calls int
+	server := testMetadataServer(time.Hour, &calls)
+	defer server.Close()
+
+	os.Setenv("OS_METADATA_URL", server.URL)
+	defer os.Unsetenv("OS_METADATA_URL")
+
+	creds := newMetadataCredentials()
+	for i := 0; i < 2; i++ {
+		v, err := creds.Get()
+		if err != nil {
+			t.Fatalf("Error fetching credentials: %s", err)
+		}
+		if v.AccessKey != "AK1" || v.SecretKey != "SK" || v.SecurityToken != "token" {
+			t.Fatalf("Unexpected credentials: %#v", v)
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("Expected valid credentials to be cached, got %d calls", calls)
+	}
+
+	// Credentials within the expiry window are fetched again.
+	calls = 0
+	expiring := testMetadataServer(time.Minute, &calls)
+	defer expiring.Close()
+
+	creds.Endpoint = expiring.URL
+	creds.expiresAt = time.Time{}
+	for i := 0; i < 2; i++ {
+		if _, err := creds.Get(); err != nil {
+			t.Fatalf("Error fetching credentials: %s", err)
+		}
+	}
+	if calls != 2 {
+		t.Fatalf("Expected expiring credentials to be refreshed, got %d calls", calls)
+	}
+}
+
+func TestMetadataCredentials_config(t *testing.T) {
+	var calls int
+	server := testMetadataServer(time.Hour, &calls)
+	defer server.Close()
+
+	os.Setenv("OS_METADATA_URL", server.URL)
+	defer os.Unsetenv("OS_METADATA_URL")
+
+	config := Config{}
+	config.loadMetadataCredentials()
+	if config.credsSource == nil || !config.usingAKSK() {
+		t.Fatal("Expected metadata credentials to be used")
+	}
+
+	creds, err := GetCredentials(&config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := creds.Get()
+	if err != nil {
+		t.Fatalf("Error getting S3 credentials: %s", err)
+	}
+	if v.AccessKeyID != "AK1" || v.SessionToken != "token" {
+		t.Fatalf("Unexpected S3 credentials: %#v", v)
+	}
+
+	server.Close()
+	config = Config{}
+	config.loadMetadataCredentials()
+	if config.credsSource != nil {
+		t.Fatal("Expected metadata credentials to be ignored when unavailable")
+	}
+}
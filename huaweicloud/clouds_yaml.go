@@ -0,0 +1,242 @@
+package huaweicloud
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+
+	homedir "github.com/mitchellh/go-homedir"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// cloudsFile represents the contents of a clouds.yaml or secure.yaml file.
+type cloudsFile struct {
+	Clouds map[string]cloudEntry `yaml:"clouds"`
+}
+
+// cloudEntry represents a single named entry of a clouds.yaml file.
+type cloudEntry struct {
+	Auth         cloudAuth `yaml:"auth"`
+	RegionName   string    `yaml:"region_name"`
+	EndpointType string    `yaml:"endpoint_type"`
+	Interface    string    `yaml:"interface"`
+	CACertFile   string    `yaml:"cacert"`
+	ClientCert   string    `yaml:"cert"`
+	ClientKey    string    `yaml:"key"`
+	Verify       *bool     `yaml:"verify"`
+}
+
+// cloudAuth represents the auth section of a clouds.yaml entry.
+type cloudAuth struct {
+	AuthURL           string `yaml:"auth_url"`
+	Token             string `yaml:"token"`
+	Username          string `yaml:"username"`
+	UserID            string `yaml:"user_id"`
+	Password          string `yaml:"password"`
+	AccessKey         string `yaml:"access_key"`
+	SecretKey         string `yaml:"secret_key"`
+	SecurityToken     string `yaml:"security_token"`
+	ProjectName       string `yaml:"project_name"`
+	ProjectID         string `yaml:"project_id"`
+	TenantName        string `yaml:"tenant_name"`
+	TenantID          string `yaml:"tenant_id"`
+	DomainName        string `yaml:"domain_name"`
+	DomainID          string `yaml:"domain_id"`
+	UserDomainName    string `yaml:"user_domain_name"`
+	UserDomainID      string `yaml:"user_domain_id"`
+	ProjectDomainName string `yaml:"project_domain_name"`
+	ProjectDomainID   string `yaml:"project_domain_id"`
+	DefaultDomain     string `yaml:"default_domain"`
+}
+
+// cloudsSearchPaths returns the locations searched for the given file name,
+// in order of precedence: the current directory, the user's
+// ~/.config/openstack directory and /etc/openstack.
+func cloudsSearchPaths(name string) []string {
+	paths := []string{}
+
+	if cwd, err := os.Getwd(); err == nil {
+		paths = append(paths, filepath.Join(cwd, name))
+	}
+
+	if home, err := homedir.Dir(); err == nil {
+		paths = append(paths, filepath.Join(home, ".config", "openstack", name))
+	}
+
+	return append(paths, filepath.Join("/etc", "openstack", name))
+}
+
+// findCloudsFile returns the path of the first existing file for the given
+// name. If envVar is set, its value is used instead of the search paths.
+func findCloudsFile(envVar, name string) (string, error) {
+	if v := os.Getenv(envVar); v != "" {
+		if _, err := os.Stat(v); err != nil {
+			return "", fmt.Errorf("Unable to read %s file %s set by %s: %s", name, v, envVar, err)
+		}
+		return v, nil
+	}
+
+	for _, p := range cloudsSearchPaths(name) {
+		if _, err := os.Stat(p); err == nil {
+			return p, nil
+		}
+	}
+
+	return "", nil
+}
+
+// readCloudsFile loads and parses a clouds.yaml style file.
+func readCloudsFile(path string) (*cloudsFile, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading %s: %s", path, err)
+	}
+
+	var clouds cloudsFile
+	if err := yaml.Unmarshal(content, &clouds); err != nil {
+		return nil, fmt.Errorf("Error parsing %s: %s", path, err)
+	}
+
+	return &clouds, nil
+}
+
+// getCloudEntry looks up the named cloud in clouds.yaml and merges in the
+// matching entry of secure.yaml, if one exists. Values from secure.yaml take
+// precedence over values from clouds.yaml.
+func getCloudEntry(name string) (*cloudEntry, error) {
+	cloudsPath, err := findCloudsFile("OS_CLIENT_CONFIG_FILE", "clouds.yaml")
+	if err != nil {
+		return nil, err
+	}
+	if cloudsPath == "" {
+		return nil, fmt.Errorf("Unable to find a clouds.yaml file for cloud %q", name)
+	}
+
+	clouds, err := readCloudsFile(cloudsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	cloud, ok := clouds.Clouds[name]
+	if !ok {
+		return nil, fmt.Errorf("Cloud %q was not found in %s", name, cloudsPath)
+	}
+	log.Printf("[DEBUG] Loaded cloud %q from %s", name, cloudsPath)
+
+	securePath, err := findCloudsFile("OS_CLIENT_SECURE_FILE", "secure.yaml")
+	if err != nil {
+		return nil, err
+	}
+	if securePath != "" {
+		secure, err := readCloudsFile(securePath)
+		if err != nil {
+			return nil, err
+		}
+		if s, ok := secure.Clouds[name]; ok {
+			log.Printf("[DEBUG] Merging secure settings of cloud %q from %s", name, securePath)
+			cloud = mergeCloudEntries(cloud, s)
+		}
+	}
+
+	return &cloud, nil
+}
+
+// mergeCloudEntries returns base with every non-empty value of override
+// applied on top of it.
+func mergeCloudEntries(base, override cloudEntry) cloudEntry {
+	a, o := &base.Auth, override.Auth
+	for dst, src := range map[*string]string{
+		&a.AuthURL:           o.AuthURL,
+		&a.Token:             o.Token,
+		&a.Username:          o.Username,
+		&a.UserID:            o.UserID,
+		&a.Password:          o.Password,
+		&a.AccessKey:         o.AccessKey,
+		&a.SecretKey:         o.SecretKey,
+		&a.SecurityToken:     o.SecurityToken,
+		&a.ProjectName:       o.ProjectName,
+		&a.ProjectID:         o.ProjectID,
+		&a.TenantName:        o.TenantName,
+		&a.TenantID:          o.TenantID,
+		&a.DomainName:        o.DomainName,
+		&a.DomainID:          o.DomainID,
+		&a.UserDomainName:    o.UserDomainName,
+		&a.UserDomainID:      o.UserDomainID,
+		&a.ProjectDomainName: o.ProjectDomainName,
+		&a.ProjectDomainID:   o.ProjectDomainID,
+		&a.DefaultDomain:     o.DefaultDomain,
+		&base.RegionName:     override.RegionName,
+		&base.EndpointType:   override.EndpointType,
+		&base.Interface:      override.Interface,
+		&base.CACertFile:     override.CACertFile,
+		&base.ClientCert:     override.ClientCert,
+		&base.ClientKey:      override.ClientKey,
+	} {
+		if src != "" {
+			*dst = src
+		}
+	}
+
+	if override.Verify != nil {
+		base.Verify = override.Verify
+	}
+
+	return base
+}
+
+// firstNonEmpty returns the first of the given values that is not empty.
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// setIfEmpty assigns value to dst unless dst has already been set.
+func setIfEmpty(dst *string, value string) {
+	if *dst == "" {
+		*dst = value
+	}
+}
+
+// loadCloudConfig fills in the Config from the clouds.yaml entry named by
+// c.Cloud. Values already set on the Config, either explicitly in the provider
+// block or through environment variables, take precedence over file values.
+func (c *Config) loadCloudConfig() error {
+	cloud, err := getCloudEntry(c.Cloud)
+	if err != nil {
+		return err
+	}
+
+	auth := cloud.Auth
+	setIfEmpty(&c.IdentityEndpoint, auth.AuthURL)
+	setIfEmpty(&c.Token, auth.Token)
+	setIfEmpty(&c.Username, auth.Username)
+	setIfEmpty(&c.UserID, auth.UserID)
+	setIfEmpty(&c.Password, auth.Password)
+	setIfEmpty(&c.AccessKey, auth.AccessKey)
+	setIfEmpty(&c.SecretKey, auth.SecretKey)
+	setIfEmpty(&c.SecurityToken, auth.SecurityToken)
+	setIfEmpty(&c.TenantID, firstNonEmpty(auth.ProjectID, auth.TenantID))
+	setIfEmpty(&c.TenantName, firstNonEmpty(auth.ProjectName, auth.TenantName))
+	setIfEmpty(&c.DomainID, firstNonEmpty(
+		auth.UserDomainID, auth.ProjectDomainID, auth.DomainID))
+	setIfEmpty(&c.DomainName, firstNonEmpty(
+		auth.UserDomainName, auth.ProjectDomainName, auth.DomainName, auth.DefaultDomain))
+
+	setIfEmpty(&c.Region, cloud.RegionName)
+	setIfEmpty(&c.EndpointType, firstNonEmpty(cloud.EndpointType, cloud.Interface))
+	setIfEmpty(&c.CACertFile, cloud.CACertFile)
+	setIfEmpty(&c.ClientCertFile, cloud.ClientCert)
+	setIfEmpty(&c.ClientKeyFile, cloud.ClientKey)
+
+	if !c.Insecure && cloud.Verify != nil && !*cloud.Verify {
+		c.Insecure = true
+	}
+
+	return nil
+}
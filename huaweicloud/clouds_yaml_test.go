@@ -0,0 +1,85 @@
+package huaweicloud
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testCloudsYAML = `
+clouds:
+  hwc:
+    region_name: cn-north-1
+    interface: internal
+    verify: false
+    auth:
+      auth_url: https://iam.example.com/v3
+      username: terraform
+      project_name: cn-north-1
+      user_domain_name: example
+`
+
+const testSecureYAML = `
+clouds:
+  hwc:
+    auth:
+      password: secret
+`
+
+func TestCloudsYAML_load(t *testing.T) {
+	dir, err := ioutil.TempDir("", "clouds")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	cloudsFile := filepath.Join(dir, "clouds.yaml")
+	secureFile := filepath.Join(dir, "secure.yaml")
+	if err := ioutil.WriteFile(cloudsFile, []byte(testCloudsYAML), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(secureFile, []byte(testSecureYAML), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	os.Setenv("OS_CLIENT_CONFIG_FILE", cloudsFile)
+	os.Setenv("OS_CLIENT_SECURE_FILE", secureFile)
+	defer os.Unsetenv("OS_CLIENT_CONFIG_FILE")
+	defer os.Unsetenv("OS_CLIENT_SECURE_FILE")
+
+	config := Config{
+		Cloud:  "hwc",
+		Region: "cn-east-2",
+	}
+	if err := config.loadCloudConfig(); err != nil {
+		t.Fatalf("Error loading clouds.yaml: %s", err)
+	}
+
+	expected := Config{
+		Cloud:            "hwc",
+		Region:           "cn-east-2",
+		IdentityEndpoint: "https://iam.example.com/v3",
+		Username:         "terraform",
+		Password:         "secret",
+		TenantName:       "cn-north-1",
+		DomainName:       "example",
+		EndpointType:     "internal",
+		Insecure:         true,
+	}
+	if config.IdentityEndpoint != expected.IdentityEndpoint ||
+		config.Username != expected.Username ||
+		config.Password != expected.Password ||
+		config.TenantName != expected.TenantName ||
+		config.DomainName != expected.DomainName ||
+		config.EndpointType != expected.EndpointType ||
+		config.Insecure != expected.Insecure ||
+		config.Region != expected.Region {
+		t.Fatalf("Expected %#v, got %#v", expected, config)
+	}
+
+	config = Config{Cloud: "missing"}
+	if err := config.loadCloudConfig(); err == nil {
+		t.Fatal("Expected an error for an unknown cloud")
+	}
+}
@@ -0,0 +1,195 @@
+package huaweicloud
+
+import (
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/gophercloud/gophercloud/openstack/blockstorage/v2/volumes"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/ports"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// computeInstanceV2SystemMetadata are the metadata keys ECS sets on the
+// servers it creates. They aren't imported into the metadata argument, and
+// neither are the keys starting with "metering.".
+var computeInstanceV2SystemMetadata = map[string]bool{
+	"charging_mode":               true,
+	"cascaded.instance_extrainfo": true,
+	"image_name":                  true,
+	"os_bit":                      true,
+	"os_type":                     true,
+	"virtual_env_type":            true,
+	"vpc_id":                      true,
+}
+
+// resourceComputeInstanceV2ImportState reconstructs the arguments of a server
+// which the Read function only keeps from the configuration: the boot volume,
+// the IDs of the networks, the security groups, the key pair, the metadata,
+// the user data and the config drive.
+func resourceComputeInstanceV2ImportState(
+	d *schema.ResourceData,
+	meta interface{}) ([]*schema.ResourceData, error) {
+
+	config := GetProjectConfig(d, meta.(*Config))
+	region := GetRegion(d, config)
+	computeClient, err := config.computeV2Client(region)
+	if err != nil {
+		return nil, fmt.Errorf("Error creating HuaweiCloud compute client: %s", err)
+	}
+
+	result := servers.Get(computeClient, d.Id())
+	server, err := result.Extract()
+	if err != nil {
+		return nil, fmt.Errorf("Error retrieving HuaweiCloud server %s: %s", d.Id(), err)
+	}
+
+	var serverWithExt struct {
+		ConfigDrive     string                   `json:"config_drive"`
+		UserData        string                   `json:"OS-EXT-SRV-ATTR:user_data"`
+		VolumesAttached []map[string]interface{} `json:"os-extended-volumes:volumes_attached"`
+	}
+	if err := result.ExtractInto(&serverWithExt); err != nil {
+		return nil, fmt.Errorf("Error retrieving HuaweiCloud server %s: %s", d.Id(), err)
+	}
+
+	// The boot volume must be known before the Read function sets the image
+	// information.
+	if len(server.Image) == 0 {
+		blockDevices, err := importInstanceBlockDevicesV2(d, config, serverWithExt.VolumesAttached)
+		if err != nil {
+			return nil, err
+		}
+		d.Set("block_device", blockDevices)
+	}
+
+	if err := resourceComputeInstanceV2Read(d, meta); err != nil {
+		return nil, err
+	}
+	if d.Id() == "" {
+		return nil, fmt.Errorf("HuaweiCloud server %s could not be found", server.ID)
+	}
+
+	// The addresses of the server only give the network names, use the ports
+	// of the server for their IDs.
+	networkClient, err := config.networkingV2Client(region)
+	if err != nil {
+		return nil, fmt.Errorf("Error creating HuaweiCloud networking client: %s", err)
+	}
+	allPages, err := ports.List(networkClient, ports.ListOpts{DeviceID: d.Id()}).AllPages()
+	if err != nil {
+		return nil, fmt.Errorf("Error listing the ports of HuaweiCloud server %s: %s", d.Id(), err)
+	}
+	allPorts, err := ports.ExtractPorts(allPages)
+	if err != nil {
+		return nil, fmt.Errorf("Error listing the ports of HuaweiCloud server %s: %s", d.Id(), err)
+	}
+
+	networks := d.Get("network").([]interface{})
+	for _, v := range networks {
+		network := v.(map[string]interface{})
+		for _, port := range allPorts {
+			if port.MACAddress == network["mac"] {
+				network["uuid"] = port.NetworkID
+			}
+		}
+		network["access_network"] = false
+	}
+	d.Set("network", networks)
+
+	// The server lists its security groups once per port.
+	secGroups := make(map[string]bool)
+	for _, sg := range server.SecurityGroups {
+		if name, ok := sg["name"].(string); ok {
+			// See resourceInstanceSecGroupsV2.
+			if name == "Sys-default" {
+				name = "default"
+			}
+			secGroups[name] = true
+		}
+	}
+	secGroupNames := make([]string, 0, len(secGroups))
+	for name := range secGroups {
+		secGroupNames = append(secGroupNames, name)
+	}
+	d.Set("security_groups", secGroupNames)
+
+	metadata := make(map[string]interface{})
+	for k, v := range server.Metadata {
+		if computeInstanceV2SystemMetadata[k] || strings.HasPrefix(k, "metering.") {
+			continue
+		}
+		metadata[k] = v
+	}
+	d.Set("metadata", metadata)
+
+	d.Set("key_pair", server.KeyName)
+	d.Set("config_drive", strings.EqualFold(serverWithExt.ConfigDrive, "true"))
+
+	// Only the hash of the user data is kept, see its StateFunc. The user data
+	// was sent base64 encoded by the Create function.
+	if v := serverWithExt.UserData; v != "" {
+		userData, err := base64.StdEncoding.DecodeString(v)
+		if err != nil {
+			return nil, fmt.Errorf("Error decoding the user data of HuaweiCloud server %s: %s", d.Id(), err)
+		}
+		hash := sha1.Sum(userData)
+		d.Set("user_data", hex.EncodeToString(hash[:]))
+	}
+
+	d.Set("stop_before_destroy", false)
+	d.Set("deletion_protection", false)
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// importInstanceBlockDevicesV2 returns the block_device entry of the boot
+// volume of a server booted from a volume created from an image. The other
+// attached volumes are left to huaweicloud_compute_volume_attach_v2.
+func importInstanceBlockDevicesV2(d *schema.ResourceData, config *Config, attachments []map[string]interface{}) ([]map[string]interface{}, error) {
+	blockStorageClient, err := config.blockStorageV2Client(GetRegion(d, config))
+	if err != nil {
+		return nil, fmt.Errorf("Error creating HuaweiCloud block storage client: %s", err)
+	}
+
+	for _, attachment := range attachments {
+		volumeID, _ := attachment["id"].(string)
+
+		var volume struct {
+			Size                int               `json:"size"`
+			Bootable            string            `json:"bootable"`
+			VolumeImageMetadata map[string]string `json:"volume_image_metadata"`
+		}
+		if err := volumes.Get(blockStorageClient, volumeID).ExtractInto(&volume); err != nil {
+			return nil, fmt.Errorf("Error retrieving HuaweiCloud volume %s: %s", volumeID, err)
+		}
+		log.Printf("[DEBUG] Retrieved volume %s of server %s: %+v", volumeID, d.Id(), volume)
+
+		if volume.Bootable != "true" {
+			continue
+		}
+
+		blockDevice := map[string]interface{}{
+			"source_type":           "image",
+			"uuid":                  volume.VolumeImageMetadata["image_id"],
+			"volume_size":           volume.Size,
+			"destination_type":      "volume",
+			"boot_index":            0,
+			"delete_on_termination": strings.EqualFold(fmt.Sprint(attachment["delete_on_termination"]), "true"),
+		}
+		// A volume which wasn't created from an image is booted as is.
+		if blockDevice["uuid"] == "" {
+			blockDevice["source_type"] = "volume"
+			blockDevice["uuid"] = volumeID
+			delete(blockDevice, "volume_size")
+		}
+
+		return []map[string]interface{}{blockDevice}, nil
+	}
+
+	return nil, nil
+}
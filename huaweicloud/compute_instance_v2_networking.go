@@ -15,6 +15,7 @@ import (
 	"os"
 
 	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/attachinterfaces"
 	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/tenantnetworks"
 	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/networks"
@@ -148,7 +149,7 @@ func getAllInstanceNetworks(d *schema.ResourceData, meta interface{}) ([]Instanc
 func getInstanceNetworkInfo(
 	d *schema.ResourceData, meta interface{}, queryType, queryTerm string) (map[string]interface{}, error) {
 
-	config := meta.(*Config)
+	config := GetProjectConfig(d, meta.(*Config))
 
 	if _, ok := os.LookupEnv("OS_NOVA_NETWORK"); !ok {
 		networkClient, err := config.networkingV2Client(GetRegion(d, config))
@@ -382,7 +383,7 @@ func expandInstanceNetworks(allInstanceNetworks []InstanceNetwork) []servers.Net
 func flattenInstanceNetworks(
 	d *schema.ResourceData, meta interface{}) ([]map[string]interface{}, error) {
 
-	config := meta.(*Config)
+	config := GetProjectConfig(d, meta.(*Config))
 	computeClient, err := config.computeV2Client(GetRegion(d, config))
 	if err != nil {
 		return nil, fmt.Errorf("Error creating HuaweiCloud compute client: %s", err)
@@ -401,6 +402,19 @@ func flattenInstanceNetworks(
 
 	networks := []map[string]interface{}{}
 
+	// The NICs of the instance are known by their MAC addresses once the
+	// instance was read. The NICs attached since then, e.g. by
+	// huaweicloud_compute_interface_attach_v2, aren't the instance's.
+	ownMACs := make(map[string]bool)
+	for _, v := range d.Get("network").([]interface{}) {
+		if mac := v.(map[string]interface{})["mac"].(string); mac != "" {
+			ownMACs[mac] = true
+		}
+	}
+	isOwnNIC := func(nic InstanceNIC) bool {
+		return len(ownMACs) == 0 || ownMACs[nic.MAC]
+	}
+
 	// If there were no instance networks returned, this means that there
 	// was not a network specified in the Terraform configuration. When this
 	// happens, the instance will be launched on a "default" network, if one
@@ -409,6 +423,9 @@ func flattenInstanceNetworks(
 	if len(allInstanceNetworks) == 0 {
 		for _, instanceAddresses := range allInstanceAddresses {
 			for _, instanceNIC := range instanceAddresses.InstanceNICs {
+				if !isOwnNIC(instanceNIC) {
+					continue
+				}
 				v := map[string]interface{}{
 					"name":        instanceAddresses.NetworkName,
 					"fixed_ip_v4": instanceNIC.FixedIPv4,
@@ -423,32 +440,232 @@ func flattenInstanceNetworks(
 		return networks, nil
 	}
 
-	// Loop through all networks and addresses, merge relevant address details.
-	for _, instanceNetwork := range allInstanceNetworks {
+	// Match the network blocks with the NICs of their networks. Only use one
+	// NIC per block since it's possible the user defined another NIC on this
+	// same network in another Terraform network block. A block keeps the NIC
+	// it was read with, the other blocks take the first NIC of their network
+	// which isn't used.
+	nics := make([]*InstanceNIC, len(allInstanceNetworks))
+	used := make(map[*InstanceNIC]bool)
+	findNIC := func(i int, match func(*InstanceNIC) bool) {
 		for _, instanceAddresses := range allInstanceAddresses {
-			if instanceNetwork.Name == instanceAddresses.NetworkName {
-				// Only use one NIC since it's possible the user defined another NIC
-				// on this same network in another Terraform network block.
-				instanceNIC := instanceAddresses.InstanceNICs[0]
-				copy(instanceAddresses.InstanceNICs, instanceAddresses.InstanceNICs[1:])
-				v := map[string]interface{}{
-					"name":           instanceAddresses.NetworkName,
-					"fixed_ip_v4":    instanceNIC.FixedIPv4,
-					"fixed_ip_v6":    instanceNIC.FixedIPv6,
-					"mac":            instanceNIC.MAC,
-					"uuid":           instanceNetwork.UUID,
-					"port":           instanceNetwork.Port,
-					"access_network": instanceNetwork.AccessNetwork,
+			if allInstanceNetworks[i].Name != instanceAddresses.NetworkName {
+				continue
+			}
+			for j := range instanceAddresses.InstanceNICs {
+				nic := &instanceAddresses.InstanceNICs[j]
+				if !used[nic] && match(nic) {
+					nics[i] = nic
+					used[nic] = true
+					return
 				}
-				networks = append(networks, v)
 			}
 		}
 	}
+	for i := range allInstanceNetworks {
+		if mac := d.Get(fmt.Sprintf("network.%d.mac", i)).(string); mac != "" {
+			findNIC(i, func(nic *InstanceNIC) bool { return nic.MAC == mac })
+		}
+	}
+	for i := range allInstanceNetworks {
+		if nics[i] == nil {
+			findNIC(i, func(nic *InstanceNIC) bool { return isOwnNIC(*nic) })
+		}
+	}
+
+	for i, instanceNetwork := range allInstanceNetworks {
+		instanceNIC := nics[i]
+		if instanceNIC == nil {
+			continue
+		}
+		v := map[string]interface{}{
+			"name":           instanceNetwork.Name,
+			"fixed_ip_v4":    instanceNIC.FixedIPv4,
+			"fixed_ip_v6":    instanceNIC.FixedIPv6,
+			"mac":            instanceNIC.MAC,
+			"uuid":           instanceNetwork.UUID,
+			"port":           instanceNetwork.Port,
+			"access_network": instanceNetwork.AccessNetwork,
+		}
+		networks = append(networks, v)
+	}
 
 	log.Printf("[DEBUG] flattenInstanceNetworks: %#v", networks)
 	return networks, nil
 }
 
+// instanceNetworkChanged returns whether the network block with the given
+// index has a new network, port or fixed IP.
+func instanceNetworkChanged(d *schema.ResourceData, i int) bool {
+	for _, field := range []string{"uuid", "name", "port", "fixed_ip_v4"} {
+		if d.HasChange(fmt.Sprintf("network.%d.%s", i, field)) {
+			return true
+		}
+	}
+	return false
+}
+
+// expandInstanceNetworkChange determines the port or network to attach for
+// the network block with the given index.
+//
+// The computed fields of a block which isn't new keep the values of the block
+// which had this index before, so only the fields which changed can be
+// trusted. The computed fields which can't are cleared in the block, so that
+// they are determined again when the instance is read.
+func expandInstanceNetworkChange(
+	d *schema.ResourceData, meta interface{}, i int, network map[string]interface{}) (InstanceNetwork, error) {
+
+	changed := func(field string) bool {
+		return d.HasChange(fmt.Sprintf("network.%d.%s", i, field)) && network[field].(string) != ""
+	}
+
+	var v InstanceNetwork
+	switch {
+	case changed("port"):
+		v.Port = network["port"].(string)
+		network["uuid"] = ""
+		network["name"] = ""
+	case changed("uuid"):
+		v.UUID = network["uuid"].(string)
+		network["name"] = ""
+		network["port"] = ""
+	case changed("name"):
+		networkInfo, err := getInstanceNetworkInfo(d, meta, "name", network["name"].(string))
+		if err != nil {
+			return v, err
+		}
+		v.UUID = networkInfo["uuid"].(string)
+		network["uuid"] = ""
+		network["port"] = ""
+	case network["port"].(string) != "":
+		v.Port = network["port"].(string)
+	default:
+		v.UUID = network["uuid"].(string)
+	}
+
+	if changed("fixed_ip_v4") && v.Port == "" {
+		v.FixedIP = network["fixed_ip_v4"].(string)
+	} else {
+		network["fixed_ip_v4"] = ""
+	}
+
+	return v, nil
+}
+
+// updateInstanceNetworks attaches the networks added to the network blocks of
+// an instance and detaches the removed ones. A NIC whose block only moved in
+// the list is kept, so that its port and fixed IPs don't change.
+func updateInstanceNetworks(
+	d *schema.ResourceData, meta interface{}, computeClient *gophercloud.ServiceClient) error {
+	config := GetProjectConfig(d, meta.(*Config))
+
+	o, n := d.GetChange("network")
+	oldNetworks, newNetworks := o.([]interface{}), n.([]interface{})
+
+	// The blocks which were removed or changed, whose NICs are detached
+	// unless they are kept.
+	var detached []map[string]interface{}
+	for i, v := range oldNetworks {
+		if i >= len(newNetworks) || instanceNetworkChanged(d, i) {
+			detached = append(detached, v.(map[string]interface{}))
+		}
+	}
+
+	// The blocks of the attached networks, which are given the MAC addresses
+	// of their NICs, as the blocks of the kept NICs are.
+	var attached []InstanceNetwork
+	var attachedBlocks []map[string]interface{}
+	for i, v := range newNetworks {
+		if i < len(oldNetworks) && !instanceNetworkChanged(d, i) {
+			continue
+		}
+
+		network := v.(map[string]interface{})
+		instanceNetwork, err := expandInstanceNetworkChange(d, meta, i, network)
+		if err != nil {
+			return err
+		}
+
+		kept := false
+		for j, old := range detached {
+			if instanceNetwork.Port != "" {
+				kept = instanceNetwork.Port == old["port"]
+			} else {
+				kept = old["port"] == "" && instanceNetwork.UUID == old["uuid"] &&
+					(instanceNetwork.FixedIP == "" || instanceNetwork.FixedIP == old["fixed_ip_v4"])
+			}
+			if kept {
+				log.Printf("[DEBUG] Keeping NIC %s of instance %s", old["mac"], d.Id())
+				network["mac"] = old["mac"]
+				detached = append(detached[:j], detached[j+1:]...)
+				break
+			}
+		}
+		if !kept {
+			network["mac"] = ""
+			attached = append(attached, instanceNetwork)
+			attachedBlocks = append(attachedBlocks, network)
+		}
+	}
+
+	if len(detached) > 0 {
+		allPages, err := attachinterfaces.List(computeClient, d.Id()).AllPages()
+		if err != nil {
+			return fmt.Errorf("Error retrieving the interfaces of HuaweiCloud server %s: %s", d.Id(), err)
+		}
+		interfaces, err := attachinterfaces.ExtractInterfaces(allPages)
+		if err != nil {
+			return fmt.Errorf("Error retrieving the interfaces of HuaweiCloud server %s: %s", d.Id(), err)
+		}
+
+		for _, old := range detached {
+			portID := findInstanceNetworkInterface(interfaces, old)
+			if portID == "" {
+				// Without its MAC address, a NIC isn't known to be detached.
+				if old["mac"] == "" {
+					return fmt.Errorf("Error detaching the NIC of network %s with address %s from "+
+						"HuaweiCloud server %s: interface not found", old["uuid"], old["fixed_ip_v4"], d.Id())
+				}
+				log.Printf("[DEBUG] NIC %s of instance %s is already detached", old["mac"], d.Id())
+				continue
+			}
+
+			log.Printf("[DEBUG] Detaching port %s from instance %s", portID, d.Id())
+			if err := attachinterfaces.Delete(computeClient, d.Id(), portID).ExtractErr(); err != nil {
+				return fmt.Errorf("Error detaching port %s from HuaweiCloud server %s: %s", portID, d.Id(), err)
+			}
+			if err := waitForInterfaceDetached(config, computeClient, d.Id(), portID, d.Timeout(schema.TimeoutUpdate)); err != nil {
+				return err
+			}
+		}
+	}
+
+	for i, v := range attached {
+		attachOpts := attachinterfaces.CreateOpts{
+			PortID:    v.Port,
+			NetworkID: v.UUID,
+		}
+		if v.FixedIP != "" {
+			attachOpts.FixedIPs = []attachinterfaces.FixedIP{{IPAddress: v.FixedIP}}
+		}
+
+		log.Printf("[DEBUG] Attaching interface to instance %s: %#v", d.Id(), attachOpts)
+		iface, err := attachinterfaces.Create(computeClient, d.Id(), attachOpts).Extract()
+		if err != nil {
+			return fmt.Errorf("Error attaching interface to HuaweiCloud server %s: %s", d.Id(), err)
+		}
+		if err := waitForInterfaceAttached(config, computeClient, d.Id(), iface.PortID, d.Timeout(schema.TimeoutUpdate)); err != nil {
+			return err
+		}
+		attachedBlocks[i]["mac"] = iface.MACAddr
+	}
+
+	// Store the blocks whose untrusted fields were cleared.
+	d.Set("network", newNetworks)
+
+	return nil
+}
+
 // getInstanceAccessAddresses determines the best IP address to communicate
 // with the instance. It does this by looping through all networks and looking
 // for a valid IP address. Priority is given to a network that was flagged as
@@ -486,3 +703,32 @@ func getInstanceAccessAddresses(
 
 	return hostv4, hostv6
 }
+
+// findInstanceNetworkInterface returns the port of the interface of a network
+// block, found by its MAC address, or by its port or its network and its fixed
+// address if the MAC address isn't known, e.g. after an import.
+func findInstanceNetworkInterface(interfaces []attachinterfaces.Interface, network map[string]interface{}) string {
+	for _, v := range interfaces {
+		switch {
+		case network["mac"] != "":
+			if v.MACAddr == network["mac"] {
+				return v.PortID
+			}
+		case network["port"] != "":
+			if v.PortID == network["port"] {
+				return v.PortID
+			}
+		case v.NetID == network["uuid"]:
+			fixedIP := network["fixed_ip_v4"]
+			if fixedIP == "" {
+				return v.PortID
+			}
+			for _, ip := range v.FixedIPs {
+				if ip.IPAddress == fixedIP {
+					return v.PortID
+				}
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,139 @@
+package huaweicloud
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/lockunlock"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/pauseunpause"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/startstop"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/suspendresume"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+// computeInstanceV2PowerStates maps the values of the power_state argument to
+// the status of the server. A locked server is an active server which can't
+// be stopped, paused, suspended or deleted until it is unlocked.
+var computeInstanceV2PowerStates = map[string]string{
+	"active":    "ACTIVE",
+	"shutoff":   "SHUTOFF",
+	"paused":    "PAUSED",
+	"suspended": "SUSPENDED",
+	"locked":    "ACTIVE",
+}
+
+// flattenInstancePowerState returns the power state of a server with the
+// given status, which is the lowercase status unless the server is locked.
+func flattenInstancePowerState(status string, locked bool) string {
+	if locked && status == "ACTIVE" {
+		return "locked"
+	}
+	return strings.ToLower(status)
+}
+
+// getInstanceLocked returns whether a server is locked.
+func getInstanceLocked(client *gophercloud.ServiceClient, id string) (bool, error) {
+	var server struct {
+		Locked bool `json:"locked"`
+	}
+	if err := servers.Get(client, id).ExtractInto(&server); err != nil {
+		return false, err
+	}
+	return server.Locked, nil
+}
+
+// setInstancePowerState brings a server into the given power state. A server
+// which isn't active is started, unpaused or resumed first, and a locked
+// server is unlocked unless it must stay locked.
+func setInstancePowerState(config *Config, client *gophercloud.ServiceClient, id, powerState string, timeout time.Duration) error {
+	target, ok := computeInstanceV2PowerStates[powerState]
+	if !ok {
+		return fmt.Errorf("Invalid power state %q for HuaweiCloud server %s", powerState, id)
+	}
+
+	server, err := servers.Get(client, id).Extract()
+	if err != nil {
+		return fmt.Errorf("Error retrieving HuaweiCloud server %s: %s", id, err)
+	}
+	locked, err := getInstanceLocked(client, id)
+	if err != nil {
+		return fmt.Errorf("Error retrieving HuaweiCloud server %s: %s", id, err)
+	}
+	log.Printf("[DEBUG] Changing the power state of server %s from %s to %s",
+		id, flattenInstancePowerState(server.Status, locked), powerState)
+
+	if locked && powerState != "locked" {
+		if err := lockunlock.Unlock(client, id).ExtractErr(); err != nil {
+			return fmt.Errorf("Error unlocking HuaweiCloud server %s: %s", id, err)
+		}
+	}
+
+	if server.Status != target {
+		if server.Status != "ACTIVE" {
+			switch server.Status {
+			case "SHUTOFF":
+				err = startstop.Start(client, id).ExtractErr()
+			case "PAUSED":
+				err = pauseunpause.Unpause(client, id).ExtractErr()
+			case "SUSPENDED":
+				err = suspendresume.Resume(client, id).ExtractErr()
+			default:
+				return fmt.Errorf("Error changing the power state of HuaweiCloud server %s: "+
+					"the server is %s", id, server.Status)
+			}
+			if err != nil {
+				return fmt.Errorf("Error starting HuaweiCloud server %s: %s", id, err)
+			}
+			if err := waitForInstancePowerState(config, client, id, server.Status, "ACTIVE", timeout); err != nil {
+				return err
+			}
+		}
+
+		switch target {
+		case "SHUTOFF":
+			err = startstop.Stop(client, id).ExtractErr()
+		case "PAUSED":
+			err = pauseunpause.Pause(client, id).ExtractErr()
+		case "SUSPENDED":
+			err = suspendresume.Suspend(client, id).ExtractErr()
+		}
+		if err != nil {
+			return fmt.Errorf("Error changing the power state of HuaweiCloud server %s to %s: %s", id, powerState, err)
+		}
+		if target != "ACTIVE" {
+			if err := waitForInstancePowerState(config, client, id, "ACTIVE", target, timeout); err != nil {
+				return err
+			}
+		}
+	}
+
+	if !locked && powerState == "locked" {
+		if err := lockunlock.Lock(client, id).ExtractErr(); err != nil {
+			return fmt.Errorf("Error locking HuaweiCloud server %s: %s", id, err)
+		}
+	}
+
+	return nil
+}
+
+func waitForInstancePowerState(config *Config, client *gophercloud.ServiceClient, id, pending, target string, timeout time.Duration) error {
+	log.Printf("[DEBUG] Waiting for instance (%s) to become %s", id, target)
+
+	stateConf := &resource.StateChangeConf{
+		Pending:    []string{pending},
+		Target:     []string{target},
+		Refresh:    ServerV2StateRefreshFunc(client, id),
+		Timeout:    timeout,
+		Delay:      config.stateRefreshDelay(10 * time.Second),
+		MinTimeout: 3 * time.Second,
+	}
+
+	if _, err := stateConf.WaitForState(); err != nil {
+		return fmt.Errorf("Error waiting for instance (%s) to become %s: %s", id, target, err)
+	}
+	return nil
+}
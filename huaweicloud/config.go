@@ -8,6 +8,8 @@ import (
 	"net/http"
 	"os"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
@@ -22,36 +24,83 @@ import (
 	"github.com/hashicorp/terraform/terraform"
 	"github.com/huaweicloud/golangsdk"
 	huaweisdk "github.com/huaweicloud/golangsdk/openstack"
+	hwtokens3 "github.com/huaweicloud/golangsdk/openstack/identity/v3/tokens"
 )
 
 type Config struct {
-	AccessKey        string
-	SecretKey        string
-	CACertFile       string
-	ClientCertFile   string
-	ClientKeyFile    string
-	Cloud            string
-	DomainID         string
-	DomainName       string
-	EndpointType     string
-	IdentityEndpoint string
-	Insecure         bool
-	Password         string
-	Region           string
-	Swauth           bool
-	TenantID         string
-	TenantName       string
-	Token            string
-	Username         string
-	UserID           string
-	useOctavia       bool
+	AccessKey                 string
+	AssumeRole                *AssumeRole
+	SecretKey                 string
+	CACertFile                string
+	ClientCertFile            string
+	ClientKeyFile             string
+	Cloud                     string
+	DefaultTags               map[string]string
+	DomainID                  string
+	Endpoints                 map[string]string
+	DomainName                string
+	EndpointType              string
+	IdentityEndpoint          string
+	Insecure                  bool
+	MaxRequestsPerSecond      int
+	MaxRetries                int
+	Password                  string
+	Region                    string
+	SecurityToken             string
+	SkipCredentialsValidation bool
+	Swauth                    bool
+	TenantID                  string
+	TenantName                string
+	Token                     string
+	Username                  string
+	UserID                    string
+	useOctavia                bool
 
 	OsClient *gophercloud.ProviderClient
 	HwClient *golangsdk.ProviderClient
 	s3sess   *session.Session
+
+	// authOnce guards the authentication, which happens on the first API
+	// request when SkipCredentialsValidation is set. It is nil for a Config
+	// which wasn't loaded with LoadAndValidate.
+	authOnce *sync.Once
+	authErr  error
+
+	// domainOnce guards the lookup of the domain of the credentials, see
+	// domainID.
+	domainOnce       *sync.Once
+	resolvedDomainID string
+	domainErr        error
+
+	akskScope      *akskScope
+	credsSource    akskCredentialsSource
+	tokenRenewer   *tokenRenewer
+	serviceClients *serviceClientCache
+	rateLimiter    *RateLimiter
+	vcrCassette    *vcrCassette
+
+	// projectConfigs holds the Configs of the other projects resources are
+	// managed in, see projectConfig. parent is the provider Config of such a
+	// project Config.
+	projectConfigs *projectConfigCache
+	parent         *Config
+
+	// skipStateRefreshDelay is set against a fake cloud, whose resources
+	// change state immediately, see stateRefreshDelay.
+	skipStateRefreshDelay bool
 }
 
 func (c *Config) LoadAndValidate() error {
+	if err := c.loadVCRCassette(); err != nil {
+		return err
+	}
+
+	if c.Cloud != "" {
+		if err := c.loadCloudConfig(); err != nil {
+			return err
+		}
+	}
+
 	validEndpoint := false
 	validEndpoints := []string{
 		"internal", "internalURL",
@@ -69,41 +118,191 @@ func (c *Config) LoadAndValidate() error {
 	if !validEndpoint {
 		return fmt.Errorf("Invalid endpoint type provided")
 	}
-	err := newopenstackClient(c)
+
+	c.serviceClients = newServiceClientCache()
+	c.projectConfigs = newProjectConfigCache()
+	c.authOnce = new(sync.Once)
+
+	// The Configs of other projects share the rate limit of the provider.
+	if c.MaxRequestsPerSecond > 0 {
+		c.rateLimiter = NewRateLimiter(c.MaxRequestsPerSecond)
+	}
+	c.domainOnce = new(sync.Once)
+
+	if c.SkipCredentialsValidation {
+		log.Printf("[DEBUG] Skipping credentials validation, authenticating on the first API request")
+		return nil
+	}
+
+	return c.authenticate()
+}
+
+// authenticate obtains the credentials and builds the provider clients, once.
+// Every later call returns the result of the first one.
+func (c *Config) authenticate() error {
+	if c.authOnce == nil {
+		return nil
+	}
+
+	c.authOnce.Do(func() {
+		c.authErr = c.loadClients()
+	})
+	return c.authErr
+}
+
+func (c *Config) loadClients() error {
+	if c.parent != nil {
+		// A project Config reuses the credentials the provider Config ended
+		// up with, only the project of the token differs.
+		if err := c.parent.authenticate(); err != nil {
+			return err
+		}
+		c.credsSource = c.parent.credsSource
+		c.DomainID = c.parent.DomainID
+		c.DomainName = c.parent.DomainName
+	} else {
+		// Fall back to the credentials of the ECS instance agency when no
+		// credentials were provided at all.
+		if c.AccessKey == "" && c.Password == "" && c.Token == "" && !c.Swauth {
+			c.loadMetadataCredentials()
+		}
+
+		if c.AssumeRole != nil {
+			if err := c.loadAgencyCredentials(); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Authenticate once with golangsdk, the gophercloud client shares its
+	// token and service catalog.
+	err := newhwClient(c)
 	if err != nil {
 		return err
 	}
 
-	return newhwClient(c)
+	err = newopenstackClient(c)
+	if err != nil {
+		return err
+	}
 
+	return newS3Session(c)
 }
 
-func newopenstackClient(c *Config) error {
-	ao := gophercloud.AuthOptions{
-		DomainID:         c.DomainID,
-		DomainName:       c.DomainName,
-		IdentityEndpoint: c.IdentityEndpoint,
-		Password:         c.Password,
-		TenantID:         c.TenantID,
-		TenantName:       c.TenantName,
-		TokenID:          c.Token,
-		Username:         c.Username,
-		UserID:           c.UserID,
+// domainID returns the ID of the domain of the credentials: DomainID if set,
+// otherwise the domain of the AK/SK or of the user of the token, which is
+// looked up once.
+func (c *Config) domainID() (string, error) {
+	if c.DomainID != "" {
+		return c.DomainID, nil
 	}
+	if err := c.authenticate(); err != nil {
+		return "", err
+	}
+	if c.domainOnce == nil {
+		return "", fmt.Errorf("Unable to determine the domain ID, set domain_id in the provider configuration")
+	}
+
+	c.domainOnce.Do(func() {
+		c.resolvedDomainID, c.domainErr = c.lookupDomainID()
+	})
+	return c.resolvedDomainID, c.domainErr
+}
 
-	client, err := openstack.NewClient(ao.IdentityEndpoint)
+func (c *Config) lookupDomainID() (string, error) {
+	if c.akskScope != nil {
+		return c.akskScope.DomainID, nil
+	}
+	if c.HwClient == nil || c.HwClient.TokenID == "" {
+		return "", fmt.Errorf("Unable to determine the domain ID without a token, " +
+			"set domain_id in the provider configuration")
+	}
+
+	identity, err := huaweisdk.NewIdentityV3(c.HwClient, golangsdk.EndpointOpts{})
 	if err != nil {
-		return err
+		return "", fmt.Errorf("Error creating HuaweiCloud identity client: %s", err)
+	}
+	user, err := hwtokens3.Get(identity, c.HwClient.TokenID).ExtractUser()
+	if err != nil {
+		return "", fmt.Errorf("Error retrieving the domain of the token: %s", err)
+	}
+	if user == nil || user.Domain.ID == "" {
+		return "", fmt.Errorf("Unable to determine the domain of the token, " +
+			"set domain_id in the provider configuration")
 	}
 
-	// Set UserAgent
-	client.UserAgent.Prepend(terraform.UserAgentString())
+	log.Printf("[DEBUG] Using domain %s of the token", user.Domain.ID)
+	return user.Domain.ID, nil
+}
+
+// projectConfigCache holds the project Configs built by a provider Config, so
+// that each project authenticates once.
+type projectConfigCache struct {
+	mu      sync.Mutex
+	configs map[string]*Config
+}
+
+func newProjectConfigCache() *projectConfigCache {
+	return &projectConfigCache{
+		configs: make(map[string]*Config),
+	}
+}
+
+// projectConfig returns a Config using the credentials of c with tokens scoped
+// to another project of the same domain. c itself is returned for its own
+// project. The project Config authenticates on its first API request, errors
+// are then returned when building service clients.
+func (c *Config) projectConfig(projectID string) *Config {
+	if projectID == "" || projectID == c.TenantID || c.projectConfigs == nil {
+		return c
+	}
+
+	c.projectConfigs.mu.Lock()
+	defer c.projectConfigs.mu.Unlock()
 
+	if config, ok := c.projectConfigs.configs[projectID]; ok {
+		return config
+	}
+
+	log.Printf("[DEBUG] Creating the Config of project %s", projectID)
+	config := *c
+	config.TenantID = projectID
+	config.TenantName = ""
+	config.AssumeRole = nil
+	config.OsClient = nil
+	config.HwClient = nil
+	config.s3sess = nil
+	config.akskScope = nil
+	config.tokenRenewer = nil
+	config.authOnce = new(sync.Once)
+	config.authErr = nil
+	config.domainOnce = new(sync.Once)
+	config.resolvedDomainID = ""
+	config.domainErr = nil
+	config.serviceClients = newServiceClientCache()
+	config.projectConfigs = nil
+	config.parent = c
+
+	c.projectConfigs.configs[projectID] = &config
+	return &config
+}
+
+// stateRefreshDelay returns the delay before the first check of a wait for a
+// resource to change state.
+func (c *Config) stateRefreshDelay(delay time.Duration) time.Duration {
+	if c.skipStateRefreshDelay {
+		return 0
+	}
+	return delay
+}
+
+// tlsConfig builds the TLS configuration shared by all HTTP clients.
+func (c *Config) tlsConfig() (*tls.Config, error) {
 	config := &tls.Config{}
 	if c.CACertFile != "" {
 		caCert, _, err := pathorcontents.Read(c.CACertFile)
 		if err != nil {
-			return fmt.Errorf("Error reading CA Cert: %s", err)
+			return nil, fmt.Errorf("Error reading CA Cert: %s", err)
 		}
 
 		caCertPool := x509.NewCertPool()
@@ -118,98 +317,72 @@ func newopenstackClient(c *Config) error {
 	if c.ClientCertFile != "" && c.ClientKeyFile != "" {
 		clientCert, _, err := pathorcontents.Read(c.ClientCertFile)
 		if err != nil {
-			return fmt.Errorf("Error reading Client Cert: %s", err)
+			return nil, fmt.Errorf("Error reading Client Cert: %s", err)
 		}
 		clientKey, _, err := pathorcontents.Read(c.ClientKeyFile)
 		if err != nil {
-			return fmt.Errorf("Error reading Client Key: %s", err)
+			return nil, fmt.Errorf("Error reading Client Key: %s", err)
 		}
 
 		cert, err := tls.X509KeyPair([]byte(clientCert), []byte(clientKey))
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		config.Certificates = []tls.Certificate{cert}
 		config.BuildNameToCertificate()
 	}
 
-	// if OS_DEBUG is set, log the requests and responses
-	var osDebug bool
-	if os.Getenv("OS_DEBUG") != "" {
-		osDebug = true
-	}
+	return config, nil
+}
 
-	transport := &http.Transport{Proxy: http.ProxyFromEnvironment, TLSClientConfig: config}
-	client.HTTPClient = http.Client{
-		Transport: &LogRoundTripper{
-			Rt:      transport,
-			OsDebug: osDebug,
-		},
-	}
+// osDebug returns true if OS_DEBUG is set, to log the requests and responses.
+func osDebug() bool {
+	return os.Getenv("OS_DEBUG") != ""
+}
 
-	// If using Swift Authentication, there's no need to validate authentication normally.
-	if !c.Swauth {
-		err = openstack.Authenticate(client, ao)
-		if err != nil {
-			return err
-		}
+// newTransport builds the RoundTripper shared by the provider clients. When
+// requests are signed with AK/SK, the signing RoundTripper is returned too.
+func (c *Config) newTransport() (http.RoundTripper, *AKSKRoundTripper, error) {
+	config, err := c.tlsConfig()
+	if err != nil {
+		return nil, nil, err
 	}
 
-	c.OsClient = client
-	//fmt.Printf("[DEBUG] Region: %s.\n", c.Region)
-
-	// Don't get AWS session unless we need it for Accesskey, SecretKey.
-	if c.AccessKey != "" && c.SecretKey != "" {
-		// Setup AWS/S3 client/config information for Swift S3 buckets
-		log.Println("[INFO] Building Swift S3 auth structure")
-		creds, err := GetCredentials(c)
-		if err != nil {
-			return err
-		}
-		// Call Get to check for credential provider. If nothing found, we'll get an
-		// error, and we can present it nicely to the user
-		cp, err := creds.Get()
-		if err != nil {
-			if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == "NoCredentialProviders" {
-				return fmt.Errorf(`No valid credential sources found for Swift S3 Provider.
-																																																																																																																																																																																																																																																																																																																																																																																																														  Please see https://terraform.io/docs/providers/aws/index.html for more information on
-																																																																																																																																																																																																																																																																																																																																																																																																														  																																																																																																																																																																																																							    providing credentials for the S3 Provider`)
-			}
-
-			return fmt.Errorf("Error loading credentials for Swift S3 Provider: %s", err)
-		}
-
-		log.Printf("[INFO] Swift S3 Auth provider used: %q", cp.ProviderName)
-
-		awsConfig := &aws.Config{
-			Credentials: creds,
-			Region:      aws.String(c.Region),
-			//MaxRetries:       aws.Int(c.MaxRetries),
-			HTTPClient: cleanhttp.DefaultClient(),
-			//S3ForcePathStyle: aws.Bool(c.S3ForcePathStyle),
-		}
+	var transport http.RoundTripper = &http.Transport{Proxy: http.ProxyFromEnvironment, TLSClientConfig: config}
+	if c.vcrCassette != nil {
+		transport = &vcrRoundTripper{Rt: transport, Cassette: c.vcrCassette}
+	}
 
-		if osDebug {
-			awsConfig.LogLevel = aws.LogLevel(aws.LogDebugWithHTTPBody | aws.LogDebugWithRequestRetries | aws.LogDebugWithRequestErrors)
-			awsConfig.Logger = awsLogger{}
-		}
+	var rt http.RoundTripper = &LogRoundTripper{
+		Rt:       transport,
+		OsDebug:  osDebug(),
+		Redactor: newRedactor(),
+	}
 
-		if c.Insecure {
-			transport := awsConfig.HTTPClient.Transport.(*http.Transport)
-			transport.TLSClientConfig = &tls.Config{
-				InsecureSkipVerify: true,
-			}
+	// When only an access key is provided, sign every request instead of
+	// obtaining a token.
+	var signer *AKSKRoundTripper
+	if c.usingAKSK() {
+		signer = &AKSKRoundTripper{
+			Rt:            rt,
+			AccessKey:     c.AccessKey,
+			SecretKey:     c.SecretKey,
+			SecurityToken: c.SecurityToken,
+			Credentials:   c.credsSource,
 		}
+		rt = signer
+	}
 
-		// Set up base session for AWS/Swift S3
-		c.s3sess, err = session.NewSession(awsConfig)
-		if err != nil {
-			return errwrap.Wrapf("Error creating Swift S3 session: {{err}}", err)
-		}
+	// Retries wrap the signing RoundTripper, so that every attempt is signed
+	// again.
+	retry := &RetryRoundTripper{
+		Rt:          rt,
+		MaxRetries:  c.MaxRetries,
+		RateLimiter: c.rateLimiter,
 	}
 
-	return nil
+	return retry, signer, nil
 }
 
 func newhwClient(c *Config) error {
@@ -233,70 +406,216 @@ func newhwClient(c *Config) error {
 	// Set UserAgent
 	client.UserAgent.Prepend(terraform.UserAgentString())
 
-	config := &tls.Config{}
-	if c.CACertFile != "" {
-		caCert, _, err := pathorcontents.Read(c.CACertFile)
+	rt, signer, err := c.newTransport()
+	if err != nil {
+		return err
+	}
+	client.HTTPClient = http.Client{Transport: rt}
+
+	if signer != nil {
+		err = akskAuthenticateHw(c, client, signer)
 		if err != nil {
-			return fmt.Errorf("Error reading CA Cert: %s", err)
+			return err
+		}
+		client.ReauthFunc = c.akskReauthFunc()
+	} else if !c.Swauth {
+		// If using Swift Authentication, there's no need to validate authentication normally.
+		err = huaweisdk.Authenticate(client, ao)
+		if err != nil {
+			return err
+		}
+		c.tokenRenewer = newTokenRenewer(client, ao)
+		client.ReauthFunc = func() error {
+			token, err := c.tokenRenewer.Renew(client.TokenID)
+			if err != nil {
+				return err
+			}
+			client.TokenID = token
+			return nil
 		}
-
-		caCertPool := x509.NewCertPool()
-		caCertPool.AppendCertsFromPEM([]byte(caCert))
-		config.RootCAs = caCertPool
 	}
 
-	if c.Insecure {
-		config.InsecureSkipVerify = true
+	c.HwClient = client
+	//fmt.Printf("[DEBUG] Region: %s.\n", c.Region)
+
+	return nil
+}
+
+// newopenstackClient builds the gophercloud ProviderClient from the
+// authenticated golangsdk ProviderClient. Both clients share the transport,
+// token and service catalog.
+func newopenstackClient(c *Config) error {
+	client, err := openstack.NewClient(c.IdentityEndpoint)
+	if err != nil {
+		return err
 	}
 
-	if c.ClientCertFile != "" && c.ClientKeyFile != "" {
-		clientCert, _, err := pathorcontents.Read(c.ClientCertFile)
-		if err != nil {
-			return fmt.Errorf("Error reading Client Cert: %s", err)
+	// Set UserAgent
+	client.UserAgent.Prepend(terraform.UserAgentString())
+
+	hwClient := c.HwClient
+	client.HTTPClient = hwClient.HTTPClient
+	client.TokenID = hwClient.TokenID
+
+	if hwClient.EndpointLocator != nil {
+		locator := hwClient.EndpointLocator
+		client.EndpointLocator = func(opts gophercloud.EndpointOpts) (string, error) {
+			return locator(golangsdk.EndpointOpts{
+				Type:         opts.Type,
+				Name:         opts.Name,
+				Region:       opts.Region,
+				Availability: golangsdk.Availability(opts.Availability),
+			})
 		}
-		clientKey, _, err := pathorcontents.Read(c.ClientKeyFile)
-		if err != nil {
-			return fmt.Errorf("Error reading Client Key: %s", err)
+	}
+
+	if c.tokenRenewer != nil {
+		client.ReauthFunc = func() error {
+			token, err := c.tokenRenewer.Renew(client.TokenID)
+			if err != nil {
+				return err
+			}
+			client.TokenID = token
+			return nil
 		}
+	} else if c.usingAKSK() {
+		client.ReauthFunc = c.akskReauthFunc()
+	}
 
-		cert, err := tls.X509KeyPair([]byte(clientCert), []byte(clientKey))
-		if err != nil {
-			return err
+	c.OsClient = client
+
+	return nil
+}
+
+// newS3Session builds the aws-sdk session used for OBS.
+func newS3Session(c *Config) error {
+	// Don't get AWS session unless we need it for Accesskey, SecretKey.
+	if (c.AccessKey == "" || c.SecretKey == "") && c.credsSource == nil {
+		return nil
+	}
+
+	// Setup AWS/S3 client/config information for Swift S3 buckets
+	log.Println("[INFO] Building Swift S3 auth structure")
+	creds, err := GetCredentials(c)
+	if err != nil {
+		return err
+	}
+	// Call Get to check for credential provider. If nothing found, we'll get an
+	// error, and we can present it nicely to the user
+	cp, err := creds.Get()
+	if err != nil {
+		if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == "NoCredentialProviders" {
+			return fmt.Errorf(`No valid credential sources found for Swift S3 Provider.
+																																																																																																																																																																																																																																																																																																																																																																																																														  Please see https://terraform.io/docs/providers/aws/index.html for more information on
+																																																																																																																																																																																																																																																																																																																																																																																																														  																																																																																																																																																																																																							    providing credentials for the S3 Provider`)
 		}
 
-		config.Certificates = []tls.Certificate{cert}
-		config.BuildNameToCertificate()
+		return fmt.Errorf("Error loading credentials for Swift S3 Provider: %s", err)
 	}
 
-	// if OS_DEBUG is set, log the requests and responses
-	var osDebug bool
-	if os.Getenv("OS_DEBUG") != "" {
-		osDebug = true
+	log.Printf("[INFO] Swift S3 Auth provider used: %q", cp.ProviderName)
+
+	awsConfig := &aws.Config{
+		Credentials: creds,
+		Region:      aws.String(c.Region),
+		//MaxRetries:       aws.Int(c.MaxRetries),
+		HTTPClient: cleanhttp.DefaultClient(),
+		//S3ForcePathStyle: aws.Bool(c.S3ForcePathStyle),
 	}
 
-	transport := &http.Transport{Proxy: http.ProxyFromEnvironment, TLSClientConfig: config}
-	client.HTTPClient = http.Client{
-		Transport: &LogRoundTripper{
-			Rt:      transport,
-			OsDebug: osDebug,
-		},
+	if osDebug() {
+		awsConfig.LogLevel = aws.LogLevel(aws.LogDebugWithHTTPBody | aws.LogDebugWithRequestRetries | aws.LogDebugWithRequestErrors)
+		awsConfig.Logger = awsLogger{redactor: newRedactor()}
 	}
 
-	// If using Swift Authentication, there's no need to validate authentication normally.
-	if !c.Swauth {
-		err = huaweisdk.Authenticate(client, ao)
-		if err != nil {
-			return err
-		}
+	tlsConfig, err := c.tlsConfig()
+	if err != nil {
+		return err
+	}
+	transport := awsConfig.HTTPClient.Transport.(*http.Transport)
+	transport.TLSClientConfig = tlsConfig
+	if c.vcrCassette != nil {
+		awsConfig.HTTPClient.Transport = &vcrRoundTripper{Rt: transport, Cassette: c.vcrCassette}
 	}
 
-	c.HwClient = client
-	//fmt.Printf("[DEBUG] Region: %s.\n", c.Region)
+	// Set up base session for AWS/Swift S3
+	c.s3sess, err = session.NewSession(awsConfig)
+	if err != nil {
+		return errwrap.Wrapf("Error creating Swift S3 session: {{err}}", err)
+	}
 
 	return nil
 }
 
-type awsLogger struct{}
+// tokenRenewer authenticates again once the token shared by the provider
+// clients has expired. Whichever client notices the expiry first renews the
+// token, the others pick up the new token. AllowReauth isn't used as it would
+// retry endlessly when the credentials themselves are no longer valid.
+type tokenRenewer struct {
+	mu    sync.Mutex
+	token string
+	renew func() (string, error)
+}
+
+func newTokenRenewer(client *golangsdk.ProviderClient, ao golangsdk.AuthOptions) *tokenRenewer {
+	template := *client
+	return &tokenRenewer{
+		token: client.TokenID,
+		renew: func() (string, error) {
+			log.Printf("[DEBUG] Re-authenticating to %s", ao.IdentityEndpoint)
+
+			// Authenticate a throw-away copy of the client without a ReauthFunc,
+			// so that a failure doesn't trigger another re-authentication.
+			tac := template
+			tac.UseTokenLock()
+			tac.ReauthFunc = nil
+			tac.TokenID = ""
+			if err := huaweisdk.Authenticate(&tac, ao); err != nil {
+				return "", err
+			}
+			return tac.TokenID, nil
+		},
+	}
+}
+
+// Renew returns a new token if stale is still the current token, or the
+// current token if it was renewed already.
+func (r *tokenRenewer) Renew(stale string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.token != stale {
+		return r.token, nil
+	}
+
+	token, err := r.renew()
+	if err != nil {
+		return "", err
+	}
+	r.token = token
+
+	return token, nil
+}
+
+// akskReauthFunc returns a ReauthFunc which renews the temporary credentials
+// requests are signed with. Static keys can't be renewed, so no ReauthFunc is
+// returned for them.
+func (c *Config) akskReauthFunc() func() error {
+	if c.credsSource == nil {
+		return nil
+	}
+
+	return func() error {
+		log.Print("[DEBUG] Renewing temporary credentials")
+		c.credsSource.Expire()
+		_, err := c.credsSource.Get()
+		return err
+	}
+}
+
+type awsLogger struct {
+	redactor *Redactor
+}
 
 func (l awsLogger) Log(args ...interface{}) {
 	tokens := make([]string, 0, len(args))
@@ -305,7 +624,7 @@ func (l awsLogger) Log(args ...interface{}) {
 			tokens = append(tokens, token)
 		}
 	}
-	log.Printf("[DEBUG] [aws-sdk-go] %s", strings.Join(tokens, " "))
+	log.Printf("[DEBUG] [aws-sdk-go] %s", l.redactor.RedactText(strings.Join(tokens, " ")))
 }
 
 func (c *Config) determineRegion(region string) string {
@@ -319,157 +638,337 @@ func (c *Config) determineRegion(region string) string {
 	return region
 }
 
+// serviceEndpoints lists the service types whose endpoint can be overridden
+// with the endpoints provider argument.
+var serviceEndpoints = []string{
+	"ces", "compute", "computev1", "database", "dns", "elb", "eps", "identity",
+	"image", "kms", "load-balancer", "nat", "network", "object-store", "obs",
+	"rds", "rdsv3", "smn", "volume", "volumev2", "vpc",
+}
+
+// serviceClientKey identifies a service client in the serviceClientCache.
+type serviceClientKey struct {
+	Service      string
+	Version      string
+	Region       string
+	EndpointType string
+	Project      string
+}
+
+// serviceClientKey returns the key of a service client of the Config. The
+// project is the ID, or the name, of the project of the Config.
+func (c *Config) serviceClientKey(service, version, region string) serviceClientKey {
+	project := c.TenantID
+	if project == "" {
+		project = c.TenantName
+	}
+	return serviceClientKey{service, version, region, c.EndpointType, project}
+}
+
+// serviceClientCache holds the service clients built by the Config, so that
+// the service catalog isn't searched again on every CRUD call.
+type serviceClientCache struct {
+	mu sync.Mutex
+	os map[serviceClientKey]*gophercloud.ServiceClient
+	hw map[serviceClientKey]*golangsdk.ServiceClient
+}
+
+func newServiceClientCache() *serviceClientCache {
+	return &serviceClientCache{
+		os: make(map[serviceClientKey]*gophercloud.ServiceClient),
+		hw: make(map[serviceClientKey]*golangsdk.ServiceClient),
+	}
+}
+
+// osServiceClient returns a gophercloud ServiceClient for the endpoint
+// override of the given service, if one is configured. Otherwise, the client is
+// built by newClient from the service catalog. resourceBase is the path
+// newClient appends to the catalog endpoint, if any. Clients are cached per
+// service, version, region, endpoint type and project. The Config authenticates
+// first if it hasn't yet.
+func (c *Config) osServiceClient(service, version, resourceBase, region string,
+	newClient func(*gophercloud.ProviderClient, gophercloud.EndpointOpts) (*gophercloud.ServiceClient, error)) (*gophercloud.ServiceClient, error) {
+	if err := c.authenticate(); err != nil {
+		return nil, err
+	}
+
+	if region == "" {
+		region = c.Region
+	}
+	key := c.serviceClientKey(service, version, region)
+
+	if c.serviceClients != nil {
+		c.serviceClients.mu.Lock()
+		defer c.serviceClients.mu.Unlock()
+		if client, ok := c.serviceClients.os[key]; ok {
+			return client, nil
+		}
+	}
+
+	var client *gophercloud.ServiceClient
+	if endpoint := c.Endpoints[service]; endpoint != "" {
+		endpoint = gophercloud.NormalizeURL(endpoint)
+		log.Printf("[DEBUG] Using the %s endpoint override: %s", service, endpoint)
+		client = &gophercloud.ServiceClient{
+			ProviderClient: c.OsClient,
+			Endpoint:       endpoint,
+			ResourceBase:   endpoint + resourceBase,
+			Type:           service,
+		}
+	} else {
+		var err error
+		client, err = newClient(c.OsClient, gophercloud.EndpointOpts{
+			Region:       c.determineRegion(region),
+			Availability: c.getEndpointType(),
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if c.serviceClients != nil {
+		c.serviceClients.os[key] = client
+	}
+	return client, nil
+}
+
+// hwServiceClient is the golangsdk equivalent of osServiceClient.
+func (c *Config) hwServiceClient(service, version, resourceBase, region string,
+	newClient func(*golangsdk.ProviderClient, golangsdk.EndpointOpts) (*golangsdk.ServiceClient, error)) (*golangsdk.ServiceClient, error) {
+	if err := c.authenticate(); err != nil {
+		return nil, err
+	}
+
+	if region == "" {
+		region = c.Region
+	}
+	key := c.serviceClientKey(service, version, region)
+
+	if c.serviceClients != nil {
+		c.serviceClients.mu.Lock()
+		defer c.serviceClients.mu.Unlock()
+		if client, ok := c.serviceClients.hw[key]; ok {
+			return client, nil
+		}
+	}
+
+	var client *golangsdk.ServiceClient
+	if endpoint := c.Endpoints[service]; endpoint != "" {
+		endpoint = golangsdk.NormalizeURL(endpoint)
+		log.Printf("[DEBUG] Using the %s endpoint override: %s", service, endpoint)
+		client = &golangsdk.ServiceClient{
+			ProviderClient: c.HwClient,
+			Endpoint:       endpoint,
+			ResourceBase:   endpoint + resourceBase,
+			Type:           service,
+		}
+	} else {
+		var err error
+		client, err = newClient(c.HwClient, golangsdk.EndpointOpts{
+			Region:       c.determineRegion(region),
+			Availability: c.getHwEndpointType(),
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if c.serviceClients != nil {
+		c.serviceClients.hw[key] = client
+	}
+	return client, nil
+}
+
 func (c *Config) computeS3conn(region string) (*s3.S3, error) {
+	if err := c.authenticate(); err != nil {
+		return nil, err
+	}
+
 	if c.s3sess == nil {
 		return nil, fmt.Errorf("Missing credentials for Swift S3 Provider, need access_key and secret_key values for provider.")
 	}
 
-	client, err := openstack.NewNetworkV2(c.OsClient, gophercloud.EndpointOpts{
-		Region:       c.determineRegion(region),
-		Availability: c.getEndpointType(),
-	})
-	// Bit of a hack, seems the only way to compute this.
-	endpoint := strings.Replace(client.Endpoint, "//vpc", "//obs", 1)
+	endpoint := c.Endpoints["obs"]
+	if endpoint == "" {
+		client, err := openstack.NewNetworkV2(c.OsClient, gophercloud.EndpointOpts{
+			Region:       c.determineRegion(region),
+			Availability: c.getEndpointType(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		// Bit of a hack, seems the only way to compute this.
+		endpoint = strings.Replace(client.Endpoint, "//vpc", "//obs", 1)
+	}
 
 	awsS3Sess := c.s3sess.Copy(&aws.Config{Endpoint: aws.String(endpoint)})
 	s3conn := s3.New(awsS3Sess)
 
-	return s3conn, err
+	return s3conn, nil
 }
 
-func (c *Config) blockStorageV1Client(region string) (*gophercloud.ServiceClient, error) {
-	return openstack.NewBlockStorageV1(c.OsClient, gophercloud.EndpointOpts{
-		Region:       c.determineRegion(region),
-		Availability: c.getEndpointType(),
+// apiServiceClient returns a client of the service of the given type in the
+// service catalog, whose resource base is the endpoint of the service. It is
+// used to call the APIs the provider has no resources for.
+func (c *Config) apiServiceClient(serviceType, region string) (*golangsdk.ServiceClient, error) {
+	return c.hwServiceClient(serviceType, "catalog", "", region, func(client *golangsdk.ProviderClient, eo golangsdk.EndpointOpts) (*golangsdk.ServiceClient, error) {
+		eo.ApplyDefaults(serviceType)
+		url, err := client.EndpointLocator(eo)
+		if err != nil {
+			return nil, err
+		}
+		return &golangsdk.ServiceClient{
+			ProviderClient: client,
+			Endpoint:       url,
+			ResourceBase:   url,
+			Type:           serviceType,
+		}, nil
 	})
 }
 
+func (c *Config) blockStorageV1Client(region string) (*gophercloud.ServiceClient, error) {
+	return c.osServiceClient("volume", "v1", "", region, openstack.NewBlockStorageV1)
+}
+
 func (c *Config) blockStorageV2Client(region string) (*gophercloud.ServiceClient, error) {
-	return openstack.NewBlockStorageV2(c.OsClient, gophercloud.EndpointOpts{
-		Region:       c.determineRegion(region),
-		Availability: c.getEndpointType(),
-	})
+	return c.osServiceClient("volumev2", "v2", "", region, openstack.NewBlockStorageV2)
 }
 
 func (c *Config) computeV2Client(region string) (*gophercloud.ServiceClient, error) {
-	return openstack.NewComputeV2(c.OsClient, gophercloud.EndpointOpts{
-		Region:       c.determineRegion(region),
-		Availability: c.getEndpointType(),
-	})
+	return c.osServiceClient("compute", "v2", "", region, openstack.NewComputeV2)
+}
+
+// computeV1Client returns a client of the ECS v1 API, which manages the tags
+// of the servers among others.
+func (c *Config) computeV1Client(region string) (*golangsdk.ServiceClient, error) {
+	return c.hwServiceClient("computev1", "v1", "", region, huaweisdk.NewComputeV1)
 }
 
 func (c *Config) dnsV2Client(region string) (*golangsdk.ServiceClient, error) {
-	return huaweisdk.NewDNSV2(c.HwClient, golangsdk.EndpointOpts{
-		//Region:       c.determineRegion(region),
-		Region:       "",
-		Availability: c.getHwEndpointType(),
+	// DNS is a global service, it isn't registered for any region in the catalog.
+	return c.hwServiceClient("dns", "v2", "v2/", "", func(client *golangsdk.ProviderClient, eo golangsdk.EndpointOpts) (*golangsdk.ServiceClient, error) {
+		eo.Region = ""
+		return huaweisdk.NewDNSV2(client, eo)
 	})
 }
 
-func (c *Config) identityV3Client(region string) (*gophercloud.ServiceClient, error) {
-	return openstack.NewIdentityV3(c.OsClient, gophercloud.EndpointOpts{
-		Region:       c.determineRegion(region),
-		Availability: c.getEndpointType(),
+func (c *Config) epsV1Client(region string) (*golangsdk.ServiceClient, error) {
+	// EPS is a global service, it isn't registered for any region in the catalog.
+	return c.hwServiceClient("eps", "v1", "v1.0/", "", func(client *golangsdk.ProviderClient, eo golangsdk.EndpointOpts) (*golangsdk.ServiceClient, error) {
+		eo.Region = ""
+		eo.ApplyDefaults("eps")
+		url, err := client.EndpointLocator(eo)
+		if err != nil {
+			return nil, err
+		}
+		return &golangsdk.ServiceClient{
+			ProviderClient: client,
+			Endpoint:       url,
+			ResourceBase:   url + "v1.0/",
+			Type:           "eps",
+		}, nil
 	})
 }
 
+// evsV2Client returns a client of the EVS v2 API, which manages the tags of
+// the volumes among others.
+func (c *Config) evsV2Client(region string) (*golangsdk.ServiceClient, error) {
+	return c.hwServiceClient("volumev2", "v2", "", region, huaweisdk.NewBlockStorageV2)
+}
+
+func (c *Config) identityV3Client(region string) (*gophercloud.ServiceClient, error) {
+	return c.osServiceClient("identity", "v3", "", region, openstack.NewIdentityV3)
+}
+
 func (c *Config) imageV2Client(region string) (*gophercloud.ServiceClient, error) {
-	return openstack.NewImageServiceV2(c.OsClient, gophercloud.EndpointOpts{
-		Region:       c.determineRegion(region),
-		Availability: c.getEndpointType(),
-	})
+	return c.osServiceClient("image", "v2", "v2/", region, openstack.NewImageServiceV2)
 }
 
 func (c *Config) networkingV1Client(region string) (*golangsdk.ServiceClient, error) {
-	return huaweisdk.NewNetworkV1(c.HwClient, golangsdk.EndpointOpts{
-		Region:       c.determineRegion(region),
-		Availability: c.getHwEndpointType(),
+	return c.hwServiceClient("vpc", "v1", "v1/", region, huaweisdk.NewNetworkV1)
+}
+
+// vpcV2Client returns a client of the VPC v2.0 API, which manages the tags of
+// the VPCs, subnets and EIPs among others.
+func (c *Config) vpcV2Client(region string) (*golangsdk.ServiceClient, error) {
+	return c.hwServiceClient("vpc", "v2", "v2.0/", region, func(client *golangsdk.ProviderClient, eo golangsdk.EndpointOpts) (*golangsdk.ServiceClient, error) {
+		sc, err := huaweisdk.NewNetworkV1(client, eo)
+		if err != nil {
+			return nil, err
+		}
+		sc.ResourceBase = sc.Endpoint + "v2.0/"
+		return sc, nil
 	})
 }
 
 func (c *Config) networkingV2Client(region string) (*gophercloud.ServiceClient, error) {
-	return openstack.NewNetworkV2(c.OsClient, gophercloud.EndpointOpts{
-		Region:       c.determineRegion(region),
-		Availability: c.getEndpointType(),
-	})
+	return c.osServiceClient("network", "v2", "v2.0/", region, openstack.NewNetworkV2)
 }
 
 func (c *Config) objectStorageV1Client(region string) (*gophercloud.ServiceClient, error) {
 	// If Swift Authentication is being used, return a swauth client.
 	if c.Swauth {
+		if err := c.authenticate(); err != nil {
+			return nil, err
+		}
+
 		return swauth.NewObjectStorageV1(c.OsClient, swauth.AuthOpts{
 			User: c.Username,
 			Key:  c.Password,
 		})
 	}
 
-	return openstack.NewObjectStorageV1(c.OsClient, gophercloud.EndpointOpts{
-		Region:       c.determineRegion(region),
-		Availability: c.getEndpointType(),
-	})
+	return c.osServiceClient("object-store", "v1", "", region, openstack.NewObjectStorageV1)
 }
 
 func (c *Config) loadBalancerV2Client(region string) (*gophercloud.ServiceClient, error) {
-	return openstack.NewLoadBalancerV2(c.OsClient, gophercloud.EndpointOpts{
-		Region:       c.determineRegion(region),
-		Availability: c.getEndpointType(),
-	})
+	return c.osServiceClient("load-balancer", "v2", "v2.0/", region, openstack.NewLoadBalancerV2)
 }
 
 func (c *Config) databaseV1Client(region string) (*gophercloud.ServiceClient, error) {
-	return openstack.NewDBV1(c.OsClient, gophercloud.EndpointOpts{
-		Region:       c.determineRegion(region),
-		Availability: c.getEndpointType(),
-	})
+	return c.osServiceClient("database", "v1", "", region, openstack.NewDBV1)
 }
 
 func (c *Config) fwV2Client(region string) (*golangsdk.ServiceClient, error) {
-	return huaweisdk.NewNetworkV2(c.HwClient, golangsdk.EndpointOpts{
-		Region:       c.determineRegion(region),
-		Availability: c.getHwEndpointType(),
-	})
+	return c.hwServiceClient("network", "v2", "v2.0/", region, huaweisdk.NewNetworkV2)
 }
 
 func (c *Config) loadElasticLoadBalancerClient(region string) (*golangsdk.ServiceClient, error) {
-	return huaweisdk.NewElasticLoadBalancer(c.HwClient, golangsdk.EndpointOpts{
-		Region:       c.determineRegion(region),
-		Availability: c.getHwEndpointType(),
-	})
+	return c.hwServiceClient("elb", "v1", "", region, huaweisdk.NewElasticLoadBalancer)
 }
 
 func (c *Config) kmsKeyV1Client(region string) (*golangsdk.ServiceClient, error) {
-	return huaweisdk.NewKmsKeyV1(c.HwClient, golangsdk.EndpointOpts{
-		Region:       c.determineRegion(region),
-		Availability: c.getHwEndpointType(),
-	})
+	return c.hwServiceClient("kms", "v1", "", region, huaweisdk.NewKmsKeyV1)
 }
 
 func (c *Config) natV2Client(region string) (*golangsdk.ServiceClient, error) {
-	return huaweisdk.NewNatV2(c.HwClient, golangsdk.EndpointOpts{
-		Region:       c.determineRegion(region),
-		Availability: c.getHwEndpointType(),
-	})
+	return c.hwServiceClient("nat", "v2", "v2.0/", region, huaweisdk.NewNatV2)
 }
 
 func (c *Config) SmnV2Client(region string) (*golangsdk.ServiceClient, error) {
-	return huaweisdk.NewSmnServiceV2(c.HwClient, golangsdk.EndpointOpts{
-		Region:       c.determineRegion(region),
-		Availability: c.getHwEndpointType(),
-	})
+	return c.hwServiceClient("smn", "v2", "notifications/", region, huaweisdk.NewSmnServiceV2)
 }
 
 func (c *Config) RdsV1Client(region string) (*golangsdk.ServiceClient, error) {
-	return huaweisdk.NewRdsServiceV1(c.HwClient, golangsdk.EndpointOpts{
-		Region:       c.determineRegion(region),
-		Availability: c.getHwEndpointType(),
+	return c.hwServiceClient("rds", "v1", "", region, huaweisdk.NewRdsServiceV1)
+}
+
+// rdsV3Client returns a client of the RDS v3 API, which manages the tags of
+// the instances among others.
+func (c *Config) rdsV3Client(region string) (*golangsdk.ServiceClient, error) {
+	return c.hwServiceClient("rdsv3", "v3", "", region, func(client *golangsdk.ProviderClient, eo golangsdk.EndpointOpts) (*golangsdk.ServiceClient, error) {
+		sc, err := huaweisdk.NewRdsServiceV1(client, eo)
+		if err != nil {
+			return nil, err
+		}
+		sc.Endpoint = strings.Replace(sc.Endpoint, "/rds/v1/", "/v3/", 1)
+		sc.ResourceBase = sc.Endpoint
+		return sc, nil
 	})
 }
 
 func (c *Config) loadCESClient(region string) (*golangsdk.ServiceClient, error) {
-	return huaweisdk.NewCESClient(c.HwClient, golangsdk.EndpointOpts{
-		Region:       c.determineRegion(region),
-		Availability: c.getHwEndpointType(),
-	})
+	return c.hwServiceClient("ces", "v1", "", region, huaweisdk.NewCESClient)
 }
 
 func (c *Config) getEndpointType() gophercloud.Availability {
@@ -0,0 +1,286 @@
+package huaweicloud
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/huaweicloud/golangsdk"
+	huaweisdk "github.com/huaweicloud/golangsdk/openstack"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/fakecloud"
+)
+
+func TestConfigEndpointOverrides(t *testing.T) {
+	config := Config{
+		Endpoints: map[string]string{
+			"network": "https://vpc.example.com",
+			"smn":     "https://smn.example.com/v2/project/",
+		},
+	}
+
+	network, err := config.networkingV2Client("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if network.Endpoint != "https://vpc.example.com/" || network.ResourceBaseURL() != "https://vpc.example.com/v2.0/" {
+		t.Fatalf("Unexpected network endpoint: %s, %s", network.Endpoint, network.ResourceBaseURL())
+	}
+
+	smn, err := config.SmnV2Client("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if smn.ResourceBaseURL() != "https://smn.example.com/v2/project/notifications/" {
+		t.Fatalf("Unexpected SMN endpoint: %s", smn.ResourceBaseURL())
+	}
+
+	_, errs := validateServiceEndpoints(map[string]interface{}{"ecs": "https://ecs.example.com"}, "endpoints")
+	if len(errs) != 1 {
+		t.Fatalf("Expected an error for an unknown service, got %v", errs)
+	}
+}
+
+func TestConfigServiceClientCache(t *testing.T) {
+	config := Config{
+		Region:         "region-1",
+		Endpoints:      map[string]string{"compute": "https://ecs.example.com"},
+		serviceClients: newServiceClientCache(),
+	}
+
+	client, err := config.computeV2Client("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cached, _ := config.computeV2Client("region-1"); cached != client {
+		t.Fatal("Expected the compute client of the provider region to be cached")
+	}
+	if other, _ := config.computeV2Client("region-2"); other == client {
+		t.Fatal("Expected a separate compute client for region-2")
+	}
+
+	config.EndpointType = "internal"
+	internal, _ := config.computeV2Client("")
+	if internal == client {
+		t.Fatal("Expected a separate compute client for the internal endpoint type")
+	}
+
+	config.TenantID = "project-2"
+	if project, _ := config.computeV2Client(""); project == internal {
+		t.Fatal("Expected a separate compute client for project-2")
+	}
+}
+
+func TestConfigSharedRateLimiter(t *testing.T) {
+	srv := fakecloud.New()
+	defer srv.Close()
+
+	config := Config{
+		IdentityEndpoint:          srv.IdentityEndpoint(),
+		Username:                  "user",
+		Password:                  "password",
+		DomainName:                "domain",
+		TenantName:                fakecloud.Region,
+		Region:                    fakecloud.Region,
+		MaxRequestsPerSecond:      10,
+		SkipCredentialsValidation: true,
+	}
+	if err := config.LoadAndValidate(); err != nil {
+		t.Fatal(err)
+	}
+	if config.rateLimiter == nil {
+		t.Fatal("Expected a rate limiter")
+	}
+
+	project := config.projectConfig("9f8e7d6c5b4a39281706f5e4d3c2b1a0")
+	if err := project.authenticate(); err != nil {
+		t.Fatal(err)
+	}
+	rt, ok := project.HwClient.HTTPClient.Transport.(*RetryRoundTripper)
+	if !ok || rt.RateLimiter != config.rateLimiter {
+		t.Fatal("Expected the project Config to share the rate limiter of the provider")
+	}
+}
+
+func TestConfigReauthentication(t *testing.T) {
+	var tokens int
+	var expired bool
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	mux.HandleFunc("/v3/auth/tokens", func(w http.ResponseWriter, r *http.Request) {
+		tokens++
+		expired = false
+		w.Header().Set("X-Subject-Token", fmt.Sprintf("token-%d", tokens))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"token":{"project":{"id":"project"},"catalog":[{"type":"compute","endpoints":[
+			{"region":"region","interface":"public","url":"%s/compute/"}]}]}}`, server.URL)
+	})
+	mux.HandleFunc("/compute/servers", func(w http.ResponseWriter, r *http.Request) {
+		// Only the latest token is valid, until it expires.
+		if expired || r.Header.Get("X-Auth-Token") != fmt.Sprintf("token-%d", tokens) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprint(w, `{"servers":[]}`)
+	})
+
+	config := Config{
+		IdentityEndpoint: server.URL + "/v3",
+		Username:         "user",
+		Password:         "password",
+		DomainName:       "domain",
+		TenantName:       "region",
+		Region:           "region",
+	}
+	if err := config.LoadAndValidate(); err != nil {
+		t.Fatalf("Error authenticating: %s", err)
+	}
+
+	if tokens != 1 {
+		t.Fatalf("Expected the clients to share a single token, got %d", tokens)
+	}
+	if config.OsClient.TokenID != config.HwClient.TokenID {
+		t.Fatalf("Expected the same token, got %s and %s", config.OsClient.TokenID, config.HwClient.TokenID)
+	}
+
+	osClient, err := config.computeV2Client("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	hwClient, err := huaweisdk.NewComputeV2(config.HwClient, golangsdk.EndpointOpts{Region: "region"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The shared token is renewed once for both clients.
+	expired = true
+	if _, err := osClient.Get(osClient.ServiceURL("servers"), nil, nil); err != nil {
+		t.Fatalf("Expected gophercloud to re-authenticate, got: %s", err)
+	}
+	if _, err := hwClient.Get(hwClient.ServiceURL("servers"), nil, nil); err != nil {
+		t.Fatalf("Expected golangsdk to re-authenticate, got: %s", err)
+	}
+	if tokens != 2 {
+		t.Fatalf("Expected a single re-authentication, got %d", tokens-1)
+	}
+}
+
+func TestConfigSkipCredentialsValidation(t *testing.T) {
+	srv := fakecloud.New()
+	defer srv.Close()
+
+	config := Config{
+		IdentityEndpoint:          srv.IdentityEndpoint(),
+		Username:                  "user",
+		Password:                  "password",
+		DomainName:                "domain",
+		TenantName:                fakecloud.Region,
+		Region:                    fakecloud.Region,
+		SkipCredentialsValidation: true,
+	}
+	if err := config.LoadAndValidate(); err != nil {
+		t.Fatal(err)
+	}
+	if requests := srv.Requests(); len(requests) != 0 {
+		t.Fatalf("Expected no request before the first API call, got %v", requests)
+	}
+
+	// Concurrent API calls authenticate once.
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := config.networkingV1Client(""); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if requests := srv.Requests(); len(requests) != 1 || requests[0] != "POST /v3/auth/tokens" {
+		t.Fatalf("Expected a single authentication, got %v", requests)
+	}
+	if config.HwClient == nil || config.OsClient == nil || config.HwClient.TokenID == "" {
+		t.Fatal("Expected the provider clients to be authenticated")
+	}
+
+	// An authentication failure is returned by every API call.
+	config = Config{
+		IdentityEndpoint:          "http://127.0.0.1:1/v3",
+		Username:                  "user",
+		Password:                  "password",
+		SkipCredentialsValidation: true,
+	}
+	if err := config.LoadAndValidate(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := config.computeV2Client(""); err == nil {
+		t.Fatal("Expected an authentication error")
+	}
+	if _, err := config.RdsV1Client(""); err == nil {
+		t.Fatal("Expected the authentication error again")
+	}
+}
+
+func TestFakeCloudReauthentication(t *testing.T) {
+	t.Parallel()
+
+	srv, config := testFakeCloud(t)
+	defer srv.Close()
+
+	srv.ExpireTokens()
+
+	r := resourceNetworkingNetworkV2()
+	state := testFakeCloudApply(t, r, nil, map[string]interface{}{"name": "network_1"}, config)
+	if _, ok := srv.Get("network/networks", state.ID); !ok {
+		t.Fatalf("Network %s not found in the fake cloud", state.ID)
+	}
+}
+
+func TestFakeCloudResourceProjectID(t *testing.T) {
+	t.Parallel()
+
+	srv, config := testFakeCloud(t)
+	defer srv.Close()
+
+	const projectID = "9f8e7d6c5b4a39281706f5e4d3c2b1a0"
+	r := resourceNetworkingNetworkV2()
+
+	state := testFakeCloudApply(t, r, nil, map[string]interface{}{
+		"name":       "network_1",
+		"project_id": projectID,
+	}, config)
+	network, _ := srv.Get("network/networks", state.ID)
+	if network["tenant_id"] != projectID {
+		t.Fatalf("Expected the network in project %s, got %v", projectID, network["tenant_id"])
+	}
+
+	state, err := r.Refresh(state, config)
+	if err != nil {
+		t.Fatalf("Error reading network: %s", err)
+	}
+	if state.Attributes["name"] != "network_1" {
+		t.Fatalf("Unexpected network name: %s", state.Attributes["name"])
+	}
+
+	// The provider and the other project each authenticated once.
+	var tokens int
+	for _, req := range srv.Requests() {
+		if req == "POST /v3/auth/tokens" {
+			tokens++
+		}
+	}
+	if tokens != 2 {
+		t.Fatalf("Expected 2 token requests, got %d", tokens)
+	}
+
+	testFakeCloudDestroy(t, r, state, config)
+	if _, ok := srv.Get("network/networks", state.ID); ok {
+		t.Fatalf("Network %s still exists", state.ID)
+	}
+}
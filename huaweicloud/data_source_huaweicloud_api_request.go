@@ -0,0 +1,92 @@
+package huaweicloud
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func dataSourceAPIRequest() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceAPIRequestRead,
+
+		Schema: map[string]*schema.Schema{
+			"region": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+			"service_type": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"method": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "GET",
+			},
+			"path": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"body": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateJsonString,
+			},
+			"headers": &schema.Schema{
+				Type:      schema.TypeMap,
+				Optional:  true,
+				Sensitive: true,
+			},
+			"result_paths": &schema.Schema{
+				Type:     schema.TypeMap,
+				Optional: true,
+			},
+			"status_code": &schema.Schema{
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"response_body": &schema.Schema{
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
+			},
+			"results": &schema.Schema{
+				Type:     schema.TypeMap,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourceAPIRequestRead(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+	client, err := apiServiceClientFromSchema(d, config)
+	if err != nil {
+		return err
+	}
+
+	region := GetRegion(d, config)
+	url, err := expandAPIPath(client, config, d.Get("path").(string), region, "")
+	if err != nil {
+		return err
+	}
+
+	method := d.Get("method").(string)
+	resp, err := sendAPIRequest(client, method, url, d.Get("body").(string), d.Get("headers").(map[string]interface{}))
+	if err != nil {
+		return fmt.Errorf("Error sending API request %s %s: %s", method, url, err)
+	}
+	log.Printf("[DEBUG] Received API response %d to %s %s", resp.StatusCode, method, url)
+
+	d.SetId(url)
+	d.Set("region", region)
+	d.Set("status_code", resp.StatusCode)
+	d.Set("response_body", resp.Body)
+	d.Set("results", flattenAPIResults(d, resp))
+
+	return nil
+}
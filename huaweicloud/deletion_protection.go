@@ -0,0 +1,27 @@
+package huaweicloud
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func deletionProtectionSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeBool,
+		Optional: true,
+		Default:  false,
+	}
+}
+
+// checkDeletionProtection returns an error if the deletion protection of the
+// resource is enabled. It is called first by the Delete functions, which also
+// run when a resource is replaced: the vendored helper/schema has no
+// CustomizeDiff to reject the replacement when planning.
+func checkDeletionProtection(d *schema.ResourceData, kind string) error {
+	if d.Get("deletion_protection").(bool) {
+		return fmt.Errorf("Error deleting %s %s: deletion_protection is enabled. "+
+			"Set it to false and apply before deleting or replacing the %s", kind, d.Id(), kind)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package huaweicloud
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/huaweicloud/golangsdk"
+)
+
+// ecsJob is the response of the asynchronous operations of the ECS v1 API.
+// Creating prepaid servers returns an order instead of a job.
+type ecsJob struct {
+	JobID     string   `json:"job_id"`
+	OrderID   string   `json:"order_id"`
+	ServerIDs []string `json:"serverIds"`
+}
+
+// ecsJobInfo is the status of an ECS job.
+type ecsJobInfo struct {
+	Status     string `json:"status"`
+	FailReason string `json:"fail_reason"`
+	ErrorCode  string `json:"error_code"`
+}
+
+// startECSJob sends a request starting an asynchronous operation of the ECS
+// v1 API and returns its job.
+func startECSJob(client *golangsdk.ServiceClient, method, url string, body interface{}) (*ecsJob, error) {
+	var job ecsJob
+	_, err := client.Request(method, url, &golangsdk.RequestOpts{
+		JSONBody:     body,
+		JSONResponse: &job,
+		OkCodes:      []int{200, 202},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func waitForECSJobSuccess(config *Config, client *golangsdk.ServiceClient, jobID string, timeout time.Duration) error {
+	target := "SUCCESS"
+
+	log.Printf("[DEBUG] Waiting for ECS job %s to become %s.", jobID, target)
+
+	stateConf := &resource.StateChangeConf{
+		Pending:    []string{"INIT", "RUNNING"},
+		Target:     []string{target},
+		Refresh:    getECSJobInfo(client, jobID),
+		Timeout:    timeout,
+		Delay:      config.stateRefreshDelay(5 * time.Second),
+		MinTimeout: 3 * time.Second,
+	}
+
+	if _, err := stateConf.WaitForState(); err != nil {
+		return fmt.Errorf("Error waiting for ECS job %s to become %s: %s", jobID, target, err)
+	}
+	return nil
+}
+
+func getECSJobInfo(client *golangsdk.ServiceClient, jobID string) resource.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		var info ecsJobInfo
+		_, err := client.Get(client.ServiceURL("jobs", jobID), &info, nil)
+		if err != nil {
+			return nil, "", err
+		}
+
+		if info.Status == "FAIL" {
+			return &info, info.Status, fmt.Errorf("%s: %s", info.ErrorCode, info.FailReason)
+		}
+		return &info, info.Status, nil
+	}
+}
@@ -0,0 +1,91 @@
+package huaweicloud
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/huaweicloud/golangsdk"
+)
+
+// defaultEnterpriseProjectID is the enterprise project resources belong to
+// when none was specified.
+const defaultEnterpriseProjectID = "0"
+
+func enterpriseProjectIDSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeString,
+		Optional: true,
+		Computed: true,
+	}
+}
+
+// The resource types of the EPS API.
+const (
+	epsResourceTypeBucket         = "bucket"
+	epsResourceTypeDNSPrivateZone = "DNS_private_zone"
+	epsResourceTypeDNSPublicZone  = "DNS_public_zone"
+	epsResourceTypeECS            = "ecs"
+	epsResourceTypeEIP            = "eip"
+	epsResourceTypeELB            = "elbs"
+	epsResourceTypeNatGateway     = "nat_gateways"
+	epsResourceTypeRDS            = "rds"
+	epsResourceTypeSecurityGroup  = "security-groups"
+)
+
+// migrateEnterpriseProject moves the resource to the enterprise project of
+// its enterprise_project_id argument with the EPS API.
+func migrateEnterpriseProject(d *schema.ResourceData, config *Config, resourceType string) error {
+	region := GetRegion(d, config)
+	epsClient, err := config.epsV1Client(region)
+	if err != nil {
+		return fmt.Errorf("Error creating HuaweiCloud EPS client: %s", err)
+	}
+
+	enterpriseProjectID := d.Get("enterprise_project_id").(string)
+	opts := map[string]interface{}{
+		"resource_type": resourceType,
+		"resource_id":   d.Id(),
+		"region_id":     region,
+		"associated":    false,
+	}
+	// Buckets are global, all other resources belong to a project.
+	if resourceType != epsResourceTypeBucket {
+		opts["project_id"] = epsClient.ProjectID
+	}
+
+	log.Printf("[DEBUG] Migrating %s %s to enterprise project %s", resourceType, d.Id(), enterpriseProjectID)
+	url := epsClient.ServiceURL("enterprise-projects", enterpriseProjectID, "resources-migrate")
+	_, err = epsClient.Post(url, opts, nil, &golangsdk.RequestOpts{
+		OkCodes: []int{204},
+	})
+	if err != nil {
+		return fmt.Errorf("Error migrating %s %s to enterprise project %s: %s",
+			resourceType, d.Id(), enterpriseProjectID, err)
+	}
+
+	return nil
+}
+
+// migrateEnterpriseProjectOnCreate migrates a resource created by an API which
+// doesn't take an enterprise project, if one was specified.
+func migrateEnterpriseProjectOnCreate(d *schema.ResourceData, config *Config, resourceType string) error {
+	if v := d.Get("enterprise_project_id").(string); v == "" || v == defaultEnterpriseProjectID {
+		return nil
+	}
+	return migrateEnterpriseProject(d, config, resourceType)
+}
+
+// setEnterpriseProjectID sets enterprise_project_id from the object under key
+// in the raw body of a response, or from the body itself if key is empty. It
+// is left unchanged if the API didn't return the field.
+func setEnterpriseProjectID(d *schema.ResourceData, body interface{}, key string) {
+	obj, _ := body.(map[string]interface{})
+	if key != "" {
+		obj, _ = obj[key].(map[string]interface{})
+	}
+
+	if v, ok := obj["enterprise_project_id"].(string); ok {
+		d.Set("enterprise_project_id", v)
+	}
+}
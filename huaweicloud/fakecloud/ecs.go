@@ -0,0 +1,352 @@
+package fakecloud
+
+import (
+	"fmt"
+	"net/http"
+)
+
+const ecsPrefix = "/ecs/v1/" + ProjectID
+
+// registerECS registers the cloudservers API of ECS, which manages the servers
+// of the compute collection through jobs.
+func (s *Server) registerECS() {
+	s.handle("POST", ecsPrefix+"/cloudservers", s.createCloudServer)
+	s.handle("POST", ecsPrefix+"/cloudservers/delete", s.deleteCloudServers)
+	s.handle("GET", ecsPrefix+"/cloudservers/{id}", s.getObject("compute/servers", "server", "Server"))
+	s.handle("PUT", ecsPrefix+"/cloudservers/{id}", s.updateServer)
+	s.handle("POST", ecsPrefix+"/cloudservers/{id}/resize", s.resizeCloudServer)
+	s.handle("POST", ecsPrefix+"/cloudservers/{id}/attachvolume", s.attachCloudServerVolume)
+	s.handle("DELETE", ecsPrefix+"/cloudservers/{id}/detachvolume/{volume}", s.detachCloudServerVolume)
+
+	s.handle("GET", ecsPrefix+"/jobs/{id}", s.getECSJob)
+}
+
+// newECSJob records an ECS job which has already succeeded and returns its ID.
+func (s *Server) newECSJob(jobType string, serverID string) string {
+	return s.add("ecs/jobs", map[string]interface{}{
+		"status":     "SUCCESS",
+		"job_type":   jobType,
+		"begin_time": timestamp(),
+		"end_time":   timestamp(),
+		"entities": map[string]interface{}{
+			"sub_jobs": []interface{}{
+				map[string]interface{}{
+					"status":   "SUCCESS",
+					"entities": map[string]interface{}{"server_id": serverID},
+				},
+			},
+		},
+	})
+}
+
+func (s *Server) getECSJob(r *request) (int, interface{}) {
+	job, ok := s.resources["ecs/jobs"][r.params[0]]
+	if !ok {
+		return notFound("Job", r.params[0])
+	}
+	return http.StatusOK, job
+}
+
+// createCloudServer creates a server with its system and data volumes, a port
+// in every subnet and its EIP if requested.
+func (s *Server) createCloudServer(r *request) (int, interface{}) {
+	opts := r.object("server")
+	name, _ := opts["name"].(string)
+	if name == "" {
+		return badRequest("Server name is required")
+	}
+	vpcID, _ := opts["vpcid"].(string)
+	if vpcID == "" {
+		return badRequest("vpcid is required")
+	}
+
+	flavorRef, _ := opts["flavorRef"].(string)
+	flavor, ok := s.resources["compute/flavors"][flavorRef]
+	if !ok {
+		return badRequest("Flavor %s could not be found.", flavorRef)
+	}
+	imageRef, _ := opts["imageRef"].(string)
+	if _, ok := s.resources["compute/images"][imageRef]; !ok {
+		return badRequest("Image %s could not be found.", imageRef)
+	}
+
+	az, _ := opts["availability_zone"].(string)
+	if az == "" {
+		az = AvailabilityZone
+	}
+
+	nics, _ := opts["nics"].([]interface{})
+	if len(nics) == 0 {
+		return badRequest("nics is required")
+	}
+	for _, v := range nics {
+		nic, _ := v.(map[string]interface{})
+		if _, ok := s.resources["network/networks"][fmt.Sprint(nic["subnet_id"])]; !ok {
+			return badRequest("Subnet %v could not be found.", nic["subnet_id"])
+		}
+	}
+
+	securityGroups := []interface{}{}
+	if groups, ok := opts["security_groups"].([]interface{}); ok {
+		for _, v := range groups {
+			id := fmt.Sprint(v.(map[string]interface{})["id"])
+			securityGroups = append(securityGroups, map[string]interface{}{"id": id, "name": id})
+		}
+	}
+
+	chargingMode := "0"
+	enterpriseProjectID := defaultEnterpriseProjectID
+	if p, ok := opts["extendparam"].(map[string]interface{}); ok {
+		if p["chargingMode"] == "prePaid" {
+			chargingMode = "1"
+		}
+		if id, _ := p["enterprise_project_id"].(string); id != "" {
+			enterpriseProjectID = id
+		}
+	}
+
+	server := map[string]interface{}{
+		"name":                        name,
+		"status":                      "ACTIVE",
+		"tenant_id":                   ProjectID,
+		"user_id":                     "fake-user",
+		"created":                     timestamp(),
+		"updated":                     timestamp(),
+		"image":                       map[string]interface{}{"id": imageRef},
+		"flavor":                      map[string]interface{}{"id": flavorRef, "name": flavor["name"]},
+		"security_groups":             securityGroups,
+		"key_name":                    opts["key_name"],
+		"config_drive":                "",
+		"OS-EXT-SRV-ATTR:user_data":   opts["user_data"],
+		"OS-EXT-AZ:availability_zone": az,
+		"OS-EXT-STS:power_state":      1,
+		"OS-EXT-STS:vm_state":         "active",
+		"locked":                      false,
+		"enterprise_project_id":       enterpriseProjectID,
+		"metadata": map[string]interface{}{
+			"charging_mode": chargingMode,
+			"vpc_id":        vpcID,
+		},
+	}
+	id := s.add("compute/servers", server)
+
+	// The system volume is the first one, the data volumes follow in order.
+	volumes := []interface{}{opts["root_volume"]}
+	if dataVolumes, ok := opts["data_volumes"].([]interface{}); ok {
+		volumes = append(volumes, dataVolumes...)
+	}
+	var attached []interface{}
+	for i, v := range volumes {
+		volumeOpts, _ := v.(map[string]interface{})
+		size, _ := volumeOpts["size"].(float64)
+		if size == 0 {
+			size = 40
+		}
+		volume := s.newVolume(fmt.Sprintf("%s-volume-%04d", name, i), volumeOpts["volumetype"], az, size)
+		if i == 0 {
+			volume["bootable"] = "true"
+			volume["volume_image_metadata"] = map[string]interface{}{"image_id": imageRef}
+		}
+		s.add("evs/volumes", volume)
+		attached = append(attached, s.attachVolume(id, volume, i))
+	}
+	server["os-extended-volumes:volumes_attached"] = attached
+
+	var addresses []interface{}
+	for _, v := range nics {
+		nic, _ := v.(map[string]interface{})
+		fixedIP, _ := nic["ip_address"].(string)
+		port := s.newPort(nic["subnet_id"].(string), fixedIP)
+		port["device_id"] = id
+		port["device_owner"] = "compute:" + az
+		port["auto_created"] = true
+		s.add("network/ports", port)
+
+		for _, ip := range port["fixed_ips"].([]interface{}) {
+			addresses = append(addresses, map[string]interface{}{
+				"addr":                    ip.(map[string]interface{})["ip_address"],
+				"version":                 4,
+				"OS-EXT-IPS:type":         "fixed",
+				"OS-EXT-IPS:port_id":      port["id"],
+				"OS-EXT-IPS-MAC:mac_addr": port["mac_address"],
+			})
+		}
+	}
+
+	if publicIP, ok := opts["publicip"].(map[string]interface{}); ok {
+		eip, _ := publicIP["eip"].(map[string]interface{})
+		address := fmt.Sprintf("198.51.100.%d", s.nextID%250+1)
+		s.add("vpc/publicips", map[string]interface{}{
+			"status":             "ACTIVE",
+			"type":               eip["iptype"],
+			"public_ip_address":  address,
+			"private_ip_address": addresses[0].(map[string]interface{})["addr"],
+			"port_id":            addresses[0].(map[string]interface{})["OS-EXT-IPS:port_id"],
+			"tenant_id":          ProjectID,
+			"create_time":        timestamp(),
+		})
+		addresses = append(addresses, map[string]interface{}{
+			"addr":            address,
+			"version":         4,
+			"OS-EXT-IPS:type": "floating",
+		})
+	}
+	server["addresses"] = map[string]interface{}{vpcID: addresses}
+
+	return http.StatusOK, map[string]interface{}{
+		"job_id":    s.newECSJob("createServer", id),
+		"serverIds": []interface{}{id},
+	}
+}
+
+// attachVolume marks a volume attached to a server as its index-th device and
+// returns the attachment of the server. It must be called with the lock held.
+func (s *Server) attachVolume(serverID string, volume map[string]interface{}, index int) map[string]interface{} {
+	device := fmt.Sprintf("/dev/vd%c", 'a'+index)
+	bootIndex := "-1"
+	if index == 0 {
+		bootIndex = "0"
+	}
+	volume["status"] = "in-use"
+	volume["attachments"] = []interface{}{
+		map[string]interface{}{
+			"server_id": serverID,
+			"volume_id": volume["id"],
+			"device":    device,
+		},
+	}
+
+	return map[string]interface{}{
+		"id":                    volume["id"],
+		"bootIndex":             bootIndex,
+		"device":                device,
+		"delete_on_termination": fmt.Sprint(index == 0),
+	}
+}
+
+func (s *Server) resizeCloudServer(r *request) (int, interface{}) {
+	server, ok := s.resources["compute/servers"][r.params[0]]
+	if !ok {
+		return notFound("Server", r.params[0])
+	}
+
+	flavorRef, _ := r.object("resize")["flavorRef"].(string)
+	flavor, ok := s.resources["compute/flavors"][flavorRef]
+	if !ok {
+		return badRequest("Flavor %s could not be found.", flavorRef)
+	}
+	server["flavor"] = map[string]interface{}{"id": flavorRef, "name": flavor["name"]}
+	server["updated"] = timestamp()
+
+	return http.StatusOK, map[string]interface{}{"job_id": s.newECSJob("resizeServer", r.params[0])}
+}
+
+func (s *Server) attachCloudServerVolume(r *request) (int, interface{}) {
+	server, ok := s.resources["compute/servers"][r.params[0]]
+	if !ok {
+		return notFound("Server", r.params[0])
+	}
+
+	volumeID, _ := r.object("volumeAttachment")["volumeId"].(string)
+	volume, ok := s.resources["evs/volumes"][volumeID]
+	if !ok {
+		return notFound("Volume", volumeID)
+	}
+	if volume["status"] != "available" {
+		return badRequest("Volume %s is %v", volumeID, volume["status"])
+	}
+
+	// Use the first free device.
+	attached, _ := server["os-extended-volumes:volumes_attached"].([]interface{})
+	used := make(map[string]bool)
+	for _, v := range attached {
+		used[fmt.Sprint(v.(map[string]interface{})["device"])] = true
+	}
+	index := 0
+	for used[fmt.Sprintf("/dev/vd%c", 'a'+index)] {
+		index++
+	}
+	server["os-extended-volumes:volumes_attached"] = append(attached, s.attachVolume(r.params[0], volume, index))
+
+	return http.StatusOK, map[string]interface{}{"job_id": s.newECSJob("attachVolume", r.params[0])}
+}
+
+func (s *Server) detachCloudServerVolume(r *request) (int, interface{}) {
+	server, ok := s.resources["compute/servers"][r.params[0]]
+	if !ok {
+		return notFound("Server", r.params[0])
+	}
+
+	attached, _ := server["os-extended-volumes:volumes_attached"].([]interface{})
+	var kept []interface{}
+	for _, v := range attached {
+		if v.(map[string]interface{})["id"] != r.params[1] {
+			kept = append(kept, v)
+		}
+	}
+	if len(kept) == len(attached) {
+		return notFound("Volume attachment", r.params[1])
+	}
+	server["os-extended-volumes:volumes_attached"] = kept
+
+	if volume, ok := s.resources["evs/volumes"][r.params[1]]; ok {
+		volume["status"] = "available"
+		volume["attachments"] = []interface{}{}
+	}
+
+	return http.StatusOK, map[string]interface{}{"job_id": s.newECSJob("detachVolume", r.params[0])}
+}
+
+// deleteCloudServers deletes servers with their ports and system volumes, and
+// their EIPs and data volumes if requested.
+func (s *Server) deleteCloudServers(r *request) (int, interface{}) {
+	servers, _ := r.body["servers"].([]interface{})
+	if len(servers) == 0 {
+		return badRequest("servers is required")
+	}
+
+	var jobID string
+	for _, v := range servers {
+		id := fmt.Sprint(v.(map[string]interface{})["id"])
+		server, ok := s.resources["compute/servers"][id]
+		if !ok {
+			return notFound("Server", id)
+		}
+		delete(s.resources["compute/servers"], id)
+
+		attached, _ := server["os-extended-volumes:volumes_attached"].([]interface{})
+		for _, a := range attached {
+			attachment := a.(map[string]interface{})
+			volumeID := fmt.Sprint(attachment["id"])
+			if attachment["bootIndex"] == "0" || r.body["delete_volume"] == true {
+				delete(s.resources["evs/volumes"], volumeID)
+			} else if volume, ok := s.resources["evs/volumes"][volumeID]; ok {
+				volume["status"] = "available"
+				volume["attachments"] = []interface{}{}
+			}
+		}
+
+		for portID, port := range s.resources["network/ports"] {
+			if port["device_id"] != id {
+				continue
+			}
+			for ipID, ip := range s.resources["vpc/publicips"] {
+				if ip["port_id"] != portID {
+					continue
+				}
+				if r.body["delete_publicip"] == true {
+					delete(s.resources["vpc/publicips"], ipID)
+				} else {
+					ip["port_id"] = ""
+					ip["private_ip_address"] = ""
+					ip["status"] = "DOWN"
+				}
+			}
+			delete(s.resources["network/ports"], portID)
+		}
+
+		jobID = s.newECSJob("deleteServer", id)
+	}
+
+	return http.StatusOK, map[string]interface{}{"job_id": jobID}
+}
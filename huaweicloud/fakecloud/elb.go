@@ -0,0 +1,103 @@
+package fakecloud
+
+import (
+	"net/http"
+)
+
+const elbPrefix = "/elb/v1.0/" + ProjectID
+
+func (s *Server) registerELB() {
+	s.handle("POST", elbPrefix+"/elbaas/loadbalancers", s.createLoadBalancer)
+	s.handle("GET", elbPrefix+"/elbaas/loadbalancers", s.listCollection("elb/loadbalancers", "loadbalancers"))
+	s.handle("GET", elbPrefix+"/elbaas/loadbalancers/{id}", s.getLoadBalancer)
+	s.handle("PUT", elbPrefix+"/elbaas/loadbalancers/{id}", s.updateLoadBalancer)
+	s.handle("DELETE", elbPrefix+"/elbaas/loadbalancers/{id}", s.deleteLoadBalancer)
+
+	s.handle("GET", elbPrefix+"/jobs/{id}", s.getJob)
+}
+
+// newJob records a job which has already succeeded and returns the response
+// of the asynchronous operation which started it.
+func (s *Server) newJob(jobType string, entities map[string]interface{}) map[string]interface{} {
+	id := s.add("elb/jobs", map[string]interface{}{
+		"status":     "SUCCESS",
+		"job_type":   jobType,
+		"entities":   entities,
+		"begin_time": timestamp(),
+		"end_time":   timestamp(),
+	})
+
+	return map[string]interface{}{
+		"uri":    "/v1.0/" + ProjectID + "/jobs/" + id,
+		"job_id": id,
+	}
+}
+
+func (s *Server) getJob(r *request) (int, interface{}) {
+	job, ok := s.resources["elb/jobs"][r.params[0]]
+	if !ok {
+		return notFound("Job", r.params[0])
+	}
+	return http.StatusOK, job
+}
+
+func (s *Server) createLoadBalancer(r *request) (int, interface{}) {
+	lb := r.body
+	if lb == nil {
+		lb = make(map[string]interface{})
+	}
+	for _, field := range []string{"name", "vpc_id", "type"} {
+		if v, _ := lb[field].(string); v == "" {
+			return badRequest("%s is required", field)
+		}
+	}
+
+	lb["status"] = "ACTIVE"
+	lb["create_time"] = timestamp()
+	lb["update_time"] = timestamp()
+	if _, ok := lb["vip_address"]; !ok {
+		lb["vip_address"] = "192.0.2.10"
+	}
+	id := s.add("elb/loadbalancers", lb)
+
+	return http.StatusOK, s.newJob("create_loadbalancer", map[string]interface{}{
+		"elb": map[string]interface{}{"id": id, "name": lb["name"]},
+	})
+}
+
+// getLoadBalancer returns a load balancer, which the API doesn't wrap in an
+// object.
+func (s *Server) getLoadBalancer(r *request) (int, interface{}) {
+	lb, ok := s.resources["elb/loadbalancers"][r.params[0]]
+	if !ok {
+		return notFound("Loadbalancer", r.params[0])
+	}
+	return http.StatusOK, lb
+}
+
+func (s *Server) updateLoadBalancer(r *request) (int, interface{}) {
+	lb, ok := s.resources["elb/loadbalancers"][r.params[0]]
+	if !ok {
+		return notFound("Loadbalancer", r.params[0])
+	}
+
+	update := r.body
+	delete(update, "id")
+	merge(lb, update)
+	lb["update_time"] = timestamp()
+
+	return http.StatusOK, s.newJob("update_loadbalancer", map[string]interface{}{
+		"elb": map[string]interface{}{"id": r.params[0]},
+	})
+}
+
+func (s *Server) deleteLoadBalancer(r *request) (int, interface{}) {
+	if _, ok := s.resources["elb/loadbalancers"][r.params[0]]; !ok {
+		return notFound("Loadbalancer", r.params[0])
+	}
+	delete(s.resources["elb/loadbalancers"], r.params[0])
+
+	return http.StatusOK, s.newJob("delete_loadbalancer", map[string]interface{}{
+		"elb": map[string]interface{}{"id": r.params[0]},
+	})
+}
@@ -0,0 +1,43 @@
+package fakecloud
+
+import (
+	"net/http"
+)
+
+const epsPrefix = "/eps/v1.0"
+
+// defaultEnterpriseProjectID is the enterprise project of resources created
+// without one.
+const defaultEnterpriseProjectID = "0"
+
+// epsCollections maps the EPS resource types to the collections holding the
+// resources.
+var epsCollections = map[string]string{
+	"ecs":             "compute/servers",
+	"eip":             "vpc/publicips",
+	"elbs":            "elb/loadbalancers",
+	"rds":             "rds/instances",
+	"security-groups": "network/security-groups",
+}
+
+func (s *Server) registerEPS() {
+	s.handle("POST", epsPrefix+"/enterprise-projects/{id}/resources-migrate", s.migrateResource)
+}
+
+// migrateResource moves a resource to the enterprise project in the path.
+func (s *Server) migrateResource(r *request) (int, interface{}) {
+	resourceType, _ := r.body["resource_type"].(string)
+	collection, ok := epsCollections[resourceType]
+	if !ok {
+		return badRequest("Unsupported resource_type %s", resourceType)
+	}
+
+	id, _ := r.body["resource_id"].(string)
+	obj, ok := s.resources[collection][id]
+	if !ok {
+		return notFound("Resource", id)
+	}
+	obj["enterprise_project_id"] = r.params[0]
+
+	return http.StatusNoContent, nil
+}
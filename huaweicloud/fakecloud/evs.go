@@ -0,0 +1,67 @@
+package fakecloud
+
+import (
+	"net/http"
+	"time"
+)
+
+const evsPrefix = "/evs/v2/" + ProjectID
+
+func (s *Server) registerEVS() {
+	s.handle("POST", evsPrefix+"/volumes", s.createVolume)
+	s.handle("GET", evsPrefix+"/volumes/{id}", s.getObject("evs/volumes", "volume", "Volume"))
+	s.handle("DELETE", evsPrefix+"/volumes/{id}", s.deleteVolume)
+}
+
+func (s *Server) createVolume(r *request) (int, interface{}) {
+	opts := r.object("volume")
+	size, _ := opts["size"].(float64)
+	if size < 1 {
+		return badRequest("volume.size must be at least 1")
+	}
+
+	volume := s.newVolume(opts["name"], opts["volume_type"], opts["availability_zone"], size)
+	s.add("evs/volumes", volume)
+
+	return http.StatusAccepted, map[string]interface{}{"volume": volume}
+}
+
+// newVolume returns an available volume, of type SATA unless another type is
+// given. It must be called with the lock held.
+func (s *Server) newVolume(name, volumeType, az interface{}, size float64) map[string]interface{} {
+	if t, _ := volumeType.(string); t == "" {
+		volumeType = "SATA"
+	}
+	if zone, _ := az.(string); zone == "" {
+		az = AvailabilityZone
+	}
+
+	// The block storage API has no time zone in its timestamps.
+	now := time.Now().UTC().Format("2006-01-02T15:04:05.000000")
+	return map[string]interface{}{
+		"name":              name,
+		"status":            "available",
+		"size":              size,
+		"volume_type":       volumeType,
+		"availability_zone": az,
+		"bootable":          "false",
+		"attachments":       []interface{}{},
+		"metadata":          map[string]interface{}{},
+		"user_id":           "fake-user",
+		"created_at":        now,
+		"updated_at":        now,
+	}
+}
+
+func (s *Server) deleteVolume(r *request) (int, interface{}) {
+	volume, ok := s.resources["evs/volumes"][r.params[0]]
+	if !ok {
+		return notFound("Volume", r.params[0])
+	}
+	if attachments, _ := volume["attachments"].([]interface{}); len(attachments) > 0 {
+		return badRequest("Volume %s is attached", r.params[0])
+	}
+	delete(s.resources["evs/volumes"], r.params[0])
+
+	return http.StatusAccepted, nil
+}
@@ -0,0 +1,116 @@
+package fakecloud
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// catalog returns the service catalog of the fake. The ELB, KMS, SMN and RDS
+// clients derive their endpoints from the compute endpoint by replacing "ecs"
+// in it, and the NAT client from the network endpoint by replacing "vpc".
+func (s *Server) catalog() []interface{} {
+	service := func(kind, path string) map[string]interface{} {
+		return map[string]interface{}{
+			"type": kind,
+			"name": kind,
+			"endpoints": []interface{}{
+				map[string]interface{}{
+					"id":        kind,
+					"interface": "public",
+					"region":    Region,
+					"region_id": Region,
+					"url":       s.URL + path,
+				},
+			},
+		}
+	}
+
+	return []interface{}{
+		service("identity", "/v3/"),
+		service("compute", "/ecs/v2/"+ProjectID+"/"),
+		service("network", "/vpc/"),
+		service("volumev2", "/evs/v2/"+ProjectID+"/"),
+		service("eps", "/eps/"),
+	}
+}
+
+func (s *Server) registerIdentity() {
+	s.handlePublic("POST", "/v3/auth/tokens", s.createToken)
+	s.handle("GET", "/v3/auth/tokens", s.getToken)
+
+	// The AK/SK authentication looks up the domain, project and catalog.
+	s.handle("GET", "/v3/auth/domains", func(r *request) (int, interface{}) {
+		return http.StatusOK, map[string]interface{}{
+			"domains": []interface{}{
+				map[string]interface{}{"id": DomainID, "name": "fake-domain"},
+			},
+		}
+	})
+	s.handle("GET", "/v3/projects", func(r *request) (int, interface{}) {
+		return http.StatusOK, map[string]interface{}{
+			"projects": []interface{}{
+				map[string]interface{}{"id": ProjectID, "name": Region, "domain_id": DomainID},
+			},
+		}
+	})
+	s.handle("GET", "/v3/auth/catalog", func(r *request) (int, interface{}) {
+		return http.StatusOK, map[string]interface{}{"catalog": s.catalog()}
+	})
+}
+
+// createToken issues a token for any password or token credentials. The token
+// is scoped to the project ID of the request if any, to ProjectID otherwise.
+func (s *Server) createToken(r *request) (int, interface{}) {
+	identity, ok := r.object("auth")["identity"].(map[string]interface{})
+	if !ok {
+		return badRequest("Missing auth.identity")
+	}
+
+	project := ProjectID
+	if scope, ok := r.object("auth")["scope"].(map[string]interface{}); ok {
+		if p, ok := scope["project"].(map[string]interface{}); ok {
+			if id, _ := p["id"].(string); id != "" {
+				project = id
+			}
+		}
+	}
+
+	token := fmt.Sprintf("fake-token-%d", len(s.tokens)+s.nextID)
+	s.nextID++
+	s.tokens[token] = project
+	r.header.Set("X-Subject-Token", token)
+
+	return http.StatusCreated, map[string]interface{}{"token": s.token(project, identity["methods"])}
+}
+
+// getToken validates the token given in X-Subject-Token.
+func (s *Server) getToken(r *request) (int, interface{}) {
+	token := r.Header.Get("X-Subject-Token")
+	project, ok := s.tokens[token]
+	if !ok {
+		return notFound("Token", token)
+	}
+
+	return http.StatusOK, map[string]interface{}{"token": s.token(project, []interface{}{"token"})}
+}
+
+// token returns the body of a token scoped to the given project.
+func (s *Server) token(project string, methods interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"methods":    methods,
+		"expires_at": time.Now().Add(24 * time.Hour).UTC().Format("2006-01-02T15:04:05.000000Z"),
+		"issued_at":  time.Now().UTC().Format("2006-01-02T15:04:05.000000Z"),
+		"user": map[string]interface{}{
+			"id":     "fake-user",
+			"name":   "fake-user",
+			"domain": map[string]interface{}{"id": DomainID, "name": "fake-domain"},
+		},
+		"project": map[string]interface{}{
+			"id":     project,
+			"name":   Region,
+			"domain": map[string]interface{}{"id": DomainID, "name": "fake-domain"},
+		},
+		"catalog": s.catalog(),
+	}
+}
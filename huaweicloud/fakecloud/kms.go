@@ -0,0 +1,125 @@
+package fakecloud
+
+import (
+	"encoding/base64"
+	"net/http"
+)
+
+const kmsPrefix = "/kms/v1.0/" + ProjectID + "/kms"
+
+// The states of KMS keys.
+const (
+	kmsKeyEnabled         = "2"
+	kmsKeyDisabled        = "3"
+	kmsKeyPendingDeletion = "4"
+)
+
+func (s *Server) registerKMS() {
+	s.handle("POST", kmsPrefix+"/create-key", s.createKey)
+	s.handle("POST", kmsPrefix+"/describe-key", s.kmsKeyAction(func(key map[string]interface{}, r *request) (int, interface{}) {
+		return http.StatusOK, map[string]interface{}{"key_info": key}
+	}))
+	s.handle("POST", kmsPrefix+"/schedule-key-deletion", s.kmsKeyAction(func(key map[string]interface{}, r *request) (int, interface{}) {
+		key["key_state"] = kmsKeyPendingDeletion
+		key["scheduled_deletion_date"] = timestamp()
+		return http.StatusOK, map[string]interface{}{"key_id": key["key_id"], "key_state": key["key_state"]}
+	}))
+	s.handle("POST", kmsPrefix+"/enable-key", s.kmsKeyAction(s.setKeyState(kmsKeyEnabled)))
+	s.handle("POST", kmsPrefix+"/disable-key", s.kmsKeyAction(s.setKeyState(kmsKeyDisabled)))
+	s.handle("POST", kmsPrefix+"/update-key-alias", s.kmsKeyAction(s.updateKeyField("key_alias")))
+	s.handle("POST", kmsPrefix+"/update-key-description", s.kmsKeyAction(s.updateKeyField("key_description")))
+	s.handle("POST", kmsPrefix+"/create-datakey", s.kmsKeyAction(func(key map[string]interface{}, r *request) (int, interface{}) {
+		if key["key_state"] != kmsKeyEnabled {
+			return badRequest("The key %s is not enabled", key["key_id"])
+		}
+		return http.StatusOK, map[string]interface{}{
+			"key_id":      key["key_id"],
+			"plain_text":  "0123456789abcdef0123456789abcdef",
+			"cipher_text": base64.StdEncoding.EncodeToString([]byte("fake-cipher-text")),
+		}
+	}))
+	s.handle("POST", kmsPrefix+"/list-keys", s.listKeys)
+}
+
+func (s *Server) createKey(r *request) (int, interface{}) {
+	alias, _ := r.body["key_alias"].(string)
+	if alias == "" {
+		return badRequest("key_alias is required")
+	}
+	for _, key := range s.resources["kms/keys"] {
+		if key["key_alias"] == alias && key["key_state"] != kmsKeyPendingDeletion {
+			return badRequest("The alias %s already exists", alias)
+		}
+	}
+
+	realm, _ := r.body["realm"].(string)
+	if realm == "" {
+		realm = Region
+	}
+	key := map[string]interface{}{
+		"domain_id":               DomainID,
+		"key_alias":               alias,
+		"key_description":         r.body["key_description"],
+		"realm":                   realm,
+		"creation_date":           timestamp(),
+		"scheduled_deletion_date": "",
+		"key_state":               kmsKeyEnabled,
+		"default_key_flag":        "0",
+		"key_type":                "1",
+		"origin":                  "kms",
+	}
+	key["key_id"] = s.add("kms/keys", key)
+
+	return http.StatusOK, map[string]interface{}{"key_info": key}
+}
+
+// kmsKeyAction returns a handler calling action with the key of the key_id of
+// the request.
+func (s *Server) kmsKeyAction(action func(map[string]interface{}, *request) (int, interface{})) func(*request) (int, interface{}) {
+	return func(r *request) (int, interface{}) {
+		id, _ := r.body["key_id"].(string)
+		key, ok := s.resources["kms/keys"][id]
+		if !ok {
+			return notFound("Key", id)
+		}
+		return action(key, r)
+	}
+}
+
+func (s *Server) setKeyState(state string) func(map[string]interface{}, *request) (int, interface{}) {
+	return func(key map[string]interface{}, r *request) (int, interface{}) {
+		if key["key_state"] == kmsKeyPendingDeletion {
+			return badRequest("The key %s is pending deletion", key["key_id"])
+		}
+		key["key_state"] = state
+		return http.StatusOK, map[string]interface{}{"key_info": key}
+	}
+}
+
+func (s *Server) updateKeyField(field string) func(map[string]interface{}, *request) (int, interface{}) {
+	return func(key map[string]interface{}, r *request) (int, interface{}) {
+		key[field] = r.body[field]
+		return http.StatusOK, map[string]interface{}{"key_info": key}
+	}
+}
+
+func (s *Server) listKeys(r *request) (int, interface{}) {
+	state, _ := r.body["key_state"].(string)
+
+	ids := []interface{}{}
+	details := []interface{}{}
+	for _, key := range s.list("kms/keys", nil) {
+		if state != "" && key["key_state"] != state {
+			continue
+		}
+		ids = append(ids, key["key_id"])
+		details = append(details, key)
+	}
+
+	return http.StatusOK, map[string]interface{}{
+		"keys":        ids,
+		"key_details": details,
+		"next_marker": "",
+		"truncated":   "false",
+	}
+}
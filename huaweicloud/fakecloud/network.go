@@ -0,0 +1,248 @@
+package fakecloud
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+const networkPrefix = "/vpc/v2.0"
+
+// networkCollections are the Neutron collections served generically.
+var networkCollections = []string{
+	"networks", "subnets", "ports", "routers", "floatingips",
+	"security-groups", "security-group-rules",
+}
+
+func (s *Server) registerNetwork() {
+	for _, name := range networkCollections {
+		collection := "network/" + name
+		key := strings.Replace(strings.TrimSuffix(name, "s"), "-", "_", -1)
+		plural := strings.Replace(name, "-", "_", -1)
+		kind := strings.Title(strings.Replace(key, "_", " ", -1))
+
+		s.handle("POST", networkPrefix+"/"+name, s.createNetworkObject(collection, key))
+		s.handle("GET", networkPrefix+"/"+name, s.listCollection(collection, plural))
+		s.handle("GET", networkPrefix+"/"+name+"/{id}", s.getObject(collection, key, kind))
+		s.handle("PUT", networkPrefix+"/"+name+"/{id}", s.updateNetworkObject(collection, key, kind))
+		s.handle("DELETE", networkPrefix+"/"+name+"/{id}", s.deleteObject(collection, kind))
+	}
+
+	s.handle("PUT", networkPrefix+"/routers/{id}/add_router_interface", s.routerInterface(true))
+	s.handle("PUT", networkPrefix+"/routers/{id}/remove_router_interface", s.routerInterface(false))
+}
+
+// createNetworkObject returns a handler creating a Neutron object, with the
+// defaults Neutron would set.
+func (s *Server) createNetworkObject(collection, key string) func(*request) (int, interface{}) {
+	return func(r *request) (int, interface{}) {
+		obj := r.object(key)
+		defaults := map[string]interface{}{
+			"tenant_id":  r.project,
+			"project_id": r.project,
+		}
+
+		switch collection {
+		case "network/networks":
+			defaults["status"] = "ACTIVE"
+			defaults["admin_state_up"] = true
+			defaults["shared"] = false
+			defaults["subnets"] = []interface{}{}
+		case "network/subnets":
+			networkID, _ := obj["network_id"].(string)
+			network, ok := s.resources["network/networks"][networkID]
+			if !ok {
+				return notFound("Network", networkID)
+			}
+			cidr, _ := obj["cidr"].(string)
+			_, ipNet, err := net.ParseCIDR(cidr)
+			if err != nil {
+				return badRequest("Invalid CIDR %s", cidr)
+			}
+			defaults["ip_version"] = 4
+			defaults["enable_dhcp"] = true
+			defaults["gateway_ip"] = hostIP(ipNet, 1)
+			defaults["dns_nameservers"] = []interface{}{}
+			defaults["host_routes"] = []interface{}{}
+			defaults["allocation_pools"] = []interface{}{
+				map[string]interface{}{"start": hostIP(ipNet, 2), "end": hostIP(ipNet, 254)},
+			}
+			defer func() {
+				subnets, _ := network["subnets"].([]interface{})
+				network["subnets"] = append(subnets, obj["id"])
+			}()
+		case "network/ports":
+			networkID, _ := obj["network_id"].(string)
+			if _, ok := s.resources["network/networks"][networkID]; !ok {
+				return notFound("Network", networkID)
+			}
+			var fixedIP string
+			if ips, ok := obj["fixed_ips"].([]interface{}); ok && len(ips) > 0 {
+				fixedIP, _ = ips[0].(map[string]interface{})["ip_address"].(string)
+			}
+			defaults = s.newPort(networkID, fixedIP)
+			defaults["tenant_id"] = r.project
+			defaults["project_id"] = r.project
+			delete(obj, "fixed_ips")
+		case "network/routers":
+			defaults["status"] = "ACTIVE"
+			defaults["admin_state_up"] = true
+			defaults["routes"] = []interface{}{}
+		case "network/floatingips":
+			defaults["status"] = "DOWN"
+			defaults["floating_ip_address"] = fmt.Sprintf("203.0.113.%d", s.nextID%250+1)
+			defaults["fixed_ip_address"] = ""
+			defaults["port_id"] = ""
+		case "network/security-groups":
+			defaults["security_group_rules"] = []interface{}{}
+		case "network/security-group-rules":
+			groupID, _ := obj["security_group_id"].(string)
+			if _, ok := s.resources["network/security-groups"][groupID]; !ok {
+				return notFound("Security group", groupID)
+			}
+		}
+
+		for k, v := range defaults {
+			if _, ok := obj[k]; !ok {
+				obj[k] = v
+			}
+		}
+		s.add(collection, obj)
+
+		return http.StatusCreated, map[string]interface{}{key: obj}
+	}
+}
+
+// updateNetworkObject returns a handler updating the fields of a Neutron
+// object.
+func (s *Server) updateNetworkObject(collection, key, kind string) func(*request) (int, interface{}) {
+	return func(r *request) (int, interface{}) {
+		obj, ok := s.resources[collection][r.params[0]]
+		if !ok {
+			return notFound(kind, r.params[0])
+		}
+
+		update := r.object(key)
+		delete(update, "id")
+		merge(obj, update)
+
+		if collection == "network/floatingips" {
+			if portID, _ := obj["port_id"].(string); portID != "" {
+				obj["status"] = "ACTIVE"
+			} else {
+				obj["status"] = "DOWN"
+			}
+		}
+
+		return http.StatusOK, map[string]interface{}{key: obj}
+	}
+}
+
+// newPort returns a port of the network with an address of its first subnet,
+// fixedIP if set. It must be called with the lock held.
+func (s *Server) newPort(networkID, fixedIP string) map[string]interface{} {
+	var fixedIPs []interface{}
+	for _, subnet := range s.list("network/subnets", nil) {
+		if subnet["network_id"] != networkID {
+			continue
+		}
+		if fixedIP == "" {
+			cidr, _ := subnet["cidr"].(string)
+			if _, ipNet, err := net.ParseCIDR(cidr); err == nil {
+				fixedIP = hostIP(ipNet, 10+len(s.resources["network/ports"]))
+			}
+		}
+		fixedIPs = append(fixedIPs, map[string]interface{}{
+			"subnet_id":  subnet["id"],
+			"ip_address": fixedIP,
+		})
+		break
+	}
+	if fixedIPs == nil {
+		fixedIPs = []interface{}{}
+	}
+
+	s.nextID++
+	return map[string]interface{}{
+		"network_id":      networkID,
+		"tenant_id":       ProjectID,
+		"project_id":      ProjectID,
+		"status":          "ACTIVE",
+		"admin_state_up":  true,
+		"mac_address":     fmt.Sprintf("fa:16:3e:%02x:%02x:%02x", s.nextID>>16&0xff, s.nextID>>8&0xff, s.nextID&0xff),
+		"fixed_ips":       fixedIPs,
+		"device_id":       "",
+		"device_owner":    "",
+		"security_groups": []interface{}{},
+	}
+}
+
+// routerInterface returns a handler adding or removing an interface of a
+// router on a subnet or a port.
+func (s *Server) routerInterface(add bool) func(*request) (int, interface{}) {
+	return func(r *request) (int, interface{}) {
+		routerID := r.params[0]
+		if _, ok := s.resources["network/routers"][routerID]; !ok {
+			return notFound("Router", routerID)
+		}
+
+		subnetID, _ := r.body["subnet_id"].(string)
+		portID, _ := r.body["port_id"].(string)
+
+		if add {
+			if portID == "" {
+				subnet, ok := s.resources["network/subnets"][subnetID]
+				if !ok {
+					return notFound("Subnet", subnetID)
+				}
+				networkID, _ := subnet["network_id"].(string)
+				gateway, _ := subnet["gateway_ip"].(string)
+				port := s.newPort(networkID, gateway)
+				port["device_owner"] = "network:router_interface"
+				portID = s.add("network/ports", port)
+			}
+			port, ok := s.resources["network/ports"][portID]
+			if !ok {
+				return notFound("Port", portID)
+			}
+			port["device_id"] = routerID
+			if port["device_owner"] == "" {
+				port["device_owner"] = "network:router_interface"
+			}
+			if ips, _ := port["fixed_ips"].([]interface{}); len(ips) > 0 {
+				subnetID, _ = ips[0].(map[string]interface{})["subnet_id"].(string)
+			}
+		} else {
+			for id, port := range s.resources["network/ports"] {
+				if port["device_id"] != routerID || port["device_owner"] != "network:router_interface" {
+					continue
+				}
+				ips, _ := port["fixed_ips"].([]interface{})
+				if id == portID || (len(ips) > 0 && ips[0].(map[string]interface{})["subnet_id"] == subnetID) {
+					portID = id
+					delete(s.resources["network/ports"], id)
+					break
+				}
+			}
+		}
+
+		return http.StatusOK, map[string]interface{}{
+			"id":        routerID,
+			"subnet_id": subnetID,
+			"port_id":   portID,
+			"tenant_id": ProjectID,
+		}
+	}
+}
+
+// hostIP returns the n-th address of the network.
+func hostIP(ipNet *net.IPNet, n int) string {
+	ip := ipNet.IP.To4()
+	if ip == nil {
+		return ""
+	}
+	v := uint32(ip[0])<<24 | uint32(ip[1])<<16 | uint32(ip[2])<<8 | uint32(ip[3])
+	v += uint32(n)
+	return net.IPv4(byte(v>>24), byte(v>>16), byte(v>>8), byte(v)).String()
+}
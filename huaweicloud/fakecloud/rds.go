@@ -0,0 +1,111 @@
+package fakecloud
+
+import (
+	"net/http"
+)
+
+const rdsPrefix = "/rds/rds/v1/" + ProjectID
+
+func (s *Server) registerRDS() {
+	s.handle("GET", rdsPrefix+"/datastores/{name}/versions", s.listDatastoreVersions)
+	s.handle("GET", rdsPrefix+"/flavors", s.listCollection("rds/flavors", "flavors"))
+
+	s.handle("POST", rdsPrefix+"/instances", s.createRDSInstance)
+	s.handle("GET", rdsPrefix+"/instances", s.listCollection("rds/instances", "instances"))
+	s.handle("GET", rdsPrefix+"/instances/{id}", s.getObject("rds/instances", "instance", "Instance"))
+	s.handle("DELETE", rdsPrefix+"/instances/{id}", s.deleteRDSInstance)
+	s.handle("POST", rdsPrefix+"/instances/{id}/action", s.rdsInstanceAction)
+	s.handle("PUT", rdsPrefix+"/instances/{id}/backups/policy", s.updateRDSBackupPolicy)
+}
+
+// listDatastoreVersions lists the versions of a datastore seeded in the
+// "rds/datastores" collection with a matching "datastore" field.
+func (s *Server) listDatastoreVersions(r *request) (int, interface{}) {
+	versions := []map[string]interface{}{}
+	for _, version := range s.list("rds/datastores", nil) {
+		if version["datastore"] == r.params[0] {
+			versions = append(versions, version)
+		}
+	}
+	return http.StatusOK, map[string]interface{}{"datastores": versions}
+}
+
+func (s *Server) createRDSInstance(r *request) (int, interface{}) {
+	opts := r.object("instance")
+	name, _ := opts["name"].(string)
+	if name == "" {
+		return badRequest("instance.name is required")
+	}
+	flavorRef, _ := opts["flavorRef"].(string)
+	if flavorRef == "" {
+		return badRequest("instance.flavorRef is required")
+	}
+
+	instance := copyObject(opts)
+	delete(instance, "dbRtPd")
+	instance["status"] = "ACTIVE"
+	instance["created"] = timestamp()
+	instance["updated"] = timestamp()
+	instance["hostname"] = "192.0.2.20"
+	instance["type"] = "MySQL"
+	instance["region"] = Region
+	instance["flavor"] = map[string]interface{}{"id": flavorRef}
+	instance["dataStoreInfo"] = opts["datastore"]
+	if _, ok := instance["dbPort"]; !ok {
+		instance["dbPort"] = 3306
+	}
+	s.add("rds/instances", instance)
+
+	return http.StatusAccepted, map[string]interface{}{"instance": instance}
+}
+
+func (s *Server) deleteRDSInstance(r *request) (int, interface{}) {
+	if _, ok := s.resources["rds/instances"][r.params[0]]; !ok {
+		return notFound("Instance", r.params[0])
+	}
+	delete(s.resources["rds/instances"], r.params[0])
+
+	return http.StatusAccepted, map[string]interface{}{"instance": map[string]interface{}{"id": r.params[0]}}
+}
+
+// rdsInstanceAction resizes the volume or the flavor of an instance.
+func (s *Server) rdsInstanceAction(r *request) (int, interface{}) {
+	instance, ok := s.resources["rds/instances"][r.params[0]]
+	if !ok {
+		return notFound("Instance", r.params[0])
+	}
+
+	resize := r.object("resize")
+	if volume, ok := resize["volume"].(map[string]interface{}); ok {
+		current, _ := instance["volume"].(map[string]interface{})
+		if current == nil {
+			current = make(map[string]interface{})
+		}
+		current["size"] = volume["size"]
+		instance["volume"] = current
+	}
+	if flavorRef, ok := resize["flavorRef"]; ok {
+		instance["flavorRef"] = flavorRef
+		instance["flavor"] = map[string]interface{}{"id": flavorRef}
+	}
+	instance["updated"] = timestamp()
+
+	return http.StatusAccepted, map[string]interface{}{
+		"instance": map[string]interface{}{"id": r.params[0]},
+	}
+}
+
+func (s *Server) updateRDSBackupPolicy(r *request) (int, interface{}) {
+	instance, ok := s.resources["rds/instances"][r.params[0]]
+	if !ok {
+		return notFound("Instance", r.params[0])
+	}
+
+	policy := r.object("policy")
+	instance["backupStrategy"] = map[string]interface{}{
+		"startTime": policy["starttime"],
+		"keepDays":  policy["keepday"],
+	}
+
+	return http.StatusOK, nil
+}
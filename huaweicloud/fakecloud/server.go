@@ -0,0 +1,406 @@
+// Package fakecloud provides an in-process fake of the HuaweiCloud APIs used
+// by the provider, to drive resources through their whole lifecycle in plain
+// unit tests.
+//
+// The fake keeps its state in memory and serves a service catalog pointing
+// back to itself, so that a provider Config authenticated against
+// Server.IdentityEndpoint sends every request to the fake. It implements
+// Keystone, Nova, the ECS cloudservers API and its jobs, EVS volumes, Neutron,
+// the VPC v1 EIP and bandwidth APIs, classic ELB load balancers and their jobs,
+// KMS, SMN, RDS v1, the EPS migration of resources between enterprise projects
+// and the batch tag APIs of the services, as far as the provider uses them.
+// Operations complete immediately: servers are ACTIVE once created and ECS and
+// ELB jobs succeed at once.
+package fakecloud
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// ProjectID is the ID of the project tokens are scoped to, unless the
+	// authentication request is scoped to another project ID.
+	ProjectID = "0f1e2d3c4b5a69788796a5b4c3d2e1f0"
+	// DomainID is the ID of the domain of the user.
+	DomainID = "1a2b3c4d5e6f70819293a4b5c6d7e8f9"
+	// Region is the region of the endpoints of the service catalog.
+	Region = "fake-region-1"
+	// AvailabilityZone is the availability zone of the servers.
+	AvailabilityZone = "fake-region-1a"
+)
+
+// Server is a fake of the HuaweiCloud APIs listening on a local port.
+type Server struct {
+	// URL is the base URL of the fake, e.g. http://127.0.0.1:1234.
+	URL string
+
+	server *httptest.Server
+	routes []route
+
+	mu        sync.Mutex
+	resources map[string]map[string]map[string]interface{}
+	tokens    map[string]string
+	tags      map[string]map[string]interface{}
+	nextID    int
+	requests  []string
+}
+
+// New starts a fake server. It must be closed with Close once the test is
+// done.
+func New() *Server {
+	s := &Server{
+		resources: make(map[string]map[string]map[string]interface{}),
+		tokens:    make(map[string]string),
+		tags:      make(map[string]map[string]interface{}),
+	}
+
+	s.registerIdentity()
+	s.registerCompute()
+	s.registerECS()
+	s.registerEVS()
+	s.registerNetwork()
+	s.registerVPC()
+	s.registerELB()
+	s.registerKMS()
+	s.registerSMN()
+	s.registerRDS()
+	s.registerEPS()
+	s.registerTags()
+
+	s.server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	s.URL = s.server.URL
+	return s
+}
+
+// Close shuts the fake server down.
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// IdentityEndpoint returns the auth_url to authenticate against the fake.
+func (s *Server) IdentityEndpoint() string {
+	return s.URL + "/v3"
+}
+
+// ExpireTokens invalidates all issued tokens, so that the next requests fail
+// with 401 until the client authenticates again.
+func (s *Server) ExpireTokens() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tokens = make(map[string]string)
+}
+
+// Requests returns the method and path of every request served so far.
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]string(nil), s.requests...)
+}
+
+// Add stores a copy of obj in the given collection, e.g. "compute/flavors",
+// and returns its ID. An ID is generated if obj has none.
+func (s *Server) Add(collection string, obj map[string]interface{}) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.add(collection, copyObject(obj))
+}
+
+// Get returns a copy of the object with the given ID in the collection.
+func (s *Server) Get(collection, id string) (map[string]interface{}, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	obj, ok := s.resources[collection][id]
+	if !ok {
+		return nil, false
+	}
+	return copyObject(obj), true
+}
+
+// List returns a copy of the objects of the collection, sorted by ID.
+func (s *Server) List(collection string) []map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var objs []map[string]interface{}
+	for _, obj := range s.list(collection, nil) {
+		objs = append(objs, copyObject(obj))
+	}
+	return objs
+}
+
+// Update merges fields into the object with the given ID in the collection,
+// e.g. to simulate a change made outside of Terraform.
+func (s *Server) Update(collection, id string, fields map[string]interface{}) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	obj, ok := s.resources[collection][id]
+	if !ok {
+		return false
+	}
+	for k, v := range copyObject(fields) {
+		obj[k] = v
+	}
+	return true
+}
+
+// Delete removes the object with the given ID from the collection.
+func (s *Server) Delete(collection, id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.resources[collection][id]; !ok {
+		return false
+	}
+	delete(s.resources[collection], id)
+	return true
+}
+
+// route is an API operation served by the fake.
+type route struct {
+	method  string
+	pattern *regexp.Regexp
+	public  bool
+	handler func(*request) (int, interface{})
+}
+
+// request is a request matched by a route. Handlers are called with the lock
+// of the server held.
+type request struct {
+	*http.Request
+	params []string
+	body   map[string]interface{}
+	header http.Header
+	// project is the project the token of the request is scoped to.
+	project string
+}
+
+// handle registers the handler of an operation. {name} placeholders in the
+// path match a single path segment and are passed in the params of the
+// request, in order.
+func (s *Server) handle(method, path string, handler func(*request) (int, interface{})) {
+	s.routes = append(s.routes, route{
+		method:  method,
+		pattern: compilePath(path),
+		handler: handler,
+	})
+}
+
+// handlePublic registers the handler of an operation which doesn't require a
+// token.
+func (s *Server) handlePublic(method, path string, handler func(*request) (int, interface{})) {
+	s.handle(method, path, handler)
+	s.routes[len(s.routes)-1].public = true
+}
+
+func compilePath(path string) *regexp.Regexp {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			segments[i] = `([^/]+)`
+		} else {
+			segments[i] = regexp.QuoteMeta(segment)
+		}
+	}
+	return regexp.MustCompile("^" + strings.Join(segments, "/") + "/?$")
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = append(s.requests, r.Method+" "+r.URL.Path)
+
+	var body map[string]interface{}
+	if raw, _ := ioutil.ReadAll(r.Body); len(raw) > 0 {
+		if err := json.Unmarshal(raw, &body); err != nil {
+			writeJSON(w, http.StatusBadRequest, errorBody(http.StatusBadRequest, "Invalid JSON body: %s", err))
+			return
+		}
+	}
+
+	methodAllowed := false
+	for _, rt := range s.routes {
+		m := rt.pattern.FindStringSubmatch(r.URL.Path)
+		if m == nil {
+			continue
+		}
+		if rt.method != r.Method {
+			methodAllowed = true
+			continue
+		}
+
+		if !rt.public && !s.authorized(r) {
+			writeJSON(w, http.StatusUnauthorized, errorBody(http.StatusUnauthorized, "The request you have made requires authentication."))
+			return
+		}
+
+		params := make([]string, len(m)-1)
+		for i, p := range m[1:] {
+			params[i], _ = url.PathUnescape(p)
+		}
+
+		project := s.tokens[r.Header.Get("X-Auth-Token")]
+		if project == "" {
+			project = ProjectID
+		}
+
+		req := &request{Request: r, params: params, body: body, header: w.Header(), project: project}
+		status, resp := rt.handler(req)
+		writeJSON(w, status, resp)
+		return
+	}
+
+	if methodAllowed {
+		writeJSON(w, http.StatusMethodNotAllowed, errorBody(http.StatusMethodNotAllowed, "Method %s not allowed", r.Method))
+		return
+	}
+	writeJSON(w, http.StatusNotFound, errorBody(http.StatusNotFound, "No fake API for %s %s", r.Method, r.URL.Path))
+}
+
+// authorized returns true if the request carries a valid token, or is signed
+// with an AK/SK.
+func (s *Server) authorized(r *http.Request) bool {
+	if strings.HasPrefix(r.Header.Get("Authorization"), "SDK-HMAC-SHA256 ") {
+		return true
+	}
+	_, ok := s.tokens[r.Header.Get("X-Auth-Token")]
+	return ok
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	if body == nil {
+		w.WriteHeader(status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func errorBody(code int, format string, args ...interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"error": map[string]interface{}{
+			"code":    code,
+			"message": fmt.Sprintf(format, args...),
+		},
+	}
+}
+
+func notFound(kind, id string) (int, interface{}) {
+	return http.StatusNotFound, errorBody(http.StatusNotFound, "%s %s could not be found.", kind, id)
+}
+
+func badRequest(format string, args ...interface{}) (int, interface{}) {
+	return http.StatusBadRequest, errorBody(http.StatusBadRequest, format, args...)
+}
+
+// newID returns a new UUID-like ID. It must be called with the lock held.
+func (s *Server) newID() string {
+	s.nextID++
+	return fmt.Sprintf("%08x-fa4e-4c10-9d00-%012x", s.nextID, s.nextID)
+}
+
+// add stores obj in the collection and returns its ID. It must be called with
+// the lock held.
+func (s *Server) add(collection string, obj map[string]interface{}) string {
+	id, _ := obj["id"].(string)
+	if id == "" {
+		id = s.newID()
+		obj["id"] = id
+	}
+
+	if s.resources[collection] == nil {
+		s.resources[collection] = make(map[string]map[string]interface{})
+	}
+	s.resources[collection][id] = obj
+	return id
+}
+
+// list returns the objects of the collection matching the filters, sorted by
+// ID. Filters on fields an object doesn't have are ignored. It must be called
+// with the lock held.
+func (s *Server) list(collection string, filters url.Values) []map[string]interface{} {
+	var ids []string
+	for id := range s.resources[collection] {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var objs []map[string]interface{}
+	for _, id := range ids {
+		obj := s.resources[collection][id]
+		if matchFilters(obj, filters) {
+			objs = append(objs, obj)
+		}
+	}
+	return objs
+}
+
+// listFilters are the query parameters which aren't filters on fields.
+var listFilters = map[string]bool{
+	"limit": true, "marker": true, "offset": true, "fields": true,
+	"sort_key": true, "sort_dir": true, "page_reverse": true,
+}
+
+func matchFilters(obj map[string]interface{}, filters url.Values) bool {
+	for name, values := range filters {
+		if listFilters[name] {
+			continue
+		}
+		value, ok := obj[name]
+		if !ok {
+			continue
+		}
+		if fmt.Sprint(value) != values[0] {
+			return false
+		}
+	}
+	return true
+}
+
+// merge copies the fields of update into obj.
+func merge(obj, update map[string]interface{}) {
+	for k, v := range update {
+		obj[k] = v
+	}
+}
+
+// copyObject returns a deep copy of a JSON object.
+func copyObject(obj map[string]interface{}) map[string]interface{} {
+	raw, _ := json.Marshal(obj)
+	var c map[string]interface{}
+	json.Unmarshal(raw, &c)
+	if c == nil {
+		c = make(map[string]interface{})
+	}
+	return c
+}
+
+func timestamp() string {
+	return time.Now().UTC().Format(time.RFC3339)
+}
+
+// object returns the JSON object under key in the body of the request, or an
+// empty object.
+func (r *request) object(key string) map[string]interface{} {
+	if v, ok := r.body[key].(map[string]interface{}); ok {
+		return v
+	}
+	return make(map[string]interface{})
+}
@@ -0,0 +1,139 @@
+package fakecloud
+
+import (
+	"net/http"
+)
+
+const smnPrefix = "/smn/v2/" + ProjectID + "/notifications"
+
+func (s *Server) registerSMN() {
+	s.handle("POST", smnPrefix+"/topics", s.createTopic)
+	s.handle("GET", smnPrefix+"/topics", s.listCollection("smn/topics", "topics"))
+	s.handle("GET", smnPrefix+"/topics/{urn}", s.getTopic)
+	s.handle("PUT", smnPrefix+"/topics/{urn}", s.updateTopic)
+	s.handle("DELETE", smnPrefix+"/topics/{urn}", s.deleteTopic)
+
+	s.handle("POST", smnPrefix+"/topics/{urn}/subscriptions", s.createSubscription)
+	s.handle("GET", smnPrefix+"/topics/{urn}/subscriptions", s.listSubscriptions)
+	s.handle("GET", smnPrefix+"/subscriptions", s.listSubscriptions)
+	s.handle("DELETE", smnPrefix+"/subscriptions/{urn}", s.deleteSubscription)
+}
+
+func (s *Server) createTopic(r *request) (int, interface{}) {
+	name, _ := r.body["name"].(string)
+	if name == "" {
+		return badRequest("name is required")
+	}
+
+	urn := "urn:smn:" + Region + ":" + ProjectID + ":" + name
+	if _, ok := s.resources["smn/topics"][urn]; ok {
+		return badRequest("The topic %s already exists", name)
+	}
+	s.add("smn/topics", map[string]interface{}{
+		"id":           urn,
+		"topic_urn":    urn,
+		"name":         name,
+		"display_name": r.body["display_name"],
+		"push_policy":  0,
+		"create_time":  timestamp(),
+		"update_time":  timestamp(),
+	})
+
+	return http.StatusOK, map[string]interface{}{
+		"request_id": s.newID(),
+		"topic_urn":  urn,
+	}
+}
+
+// getTopic returns a topic, which the API doesn't wrap in an object.
+func (s *Server) getTopic(r *request) (int, interface{}) {
+	topic, ok := s.resources["smn/topics"][r.params[0]]
+	if !ok {
+		return notFound("Topic", r.params[0])
+	}
+	return http.StatusOK, topic
+}
+
+func (s *Server) updateTopic(r *request) (int, interface{}) {
+	topic, ok := s.resources["smn/topics"][r.params[0]]
+	if !ok {
+		return notFound("Topic", r.params[0])
+	}
+	if v, ok := r.body["display_name"]; ok {
+		topic["display_name"] = v
+	}
+	topic["update_time"] = timestamp()
+
+	return http.StatusOK, map[string]interface{}{"request_id": s.newID()}
+}
+
+// deleteTopic deletes a topic along with its subscriptions.
+func (s *Server) deleteTopic(r *request) (int, interface{}) {
+	if _, ok := s.resources["smn/topics"][r.params[0]]; !ok {
+		return notFound("Topic", r.params[0])
+	}
+	delete(s.resources["smn/topics"], r.params[0])
+
+	for urn, subscription := range s.resources["smn/subscriptions"] {
+		if subscription["topic_urn"] == r.params[0] {
+			delete(s.resources["smn/subscriptions"], urn)
+		}
+	}
+
+	return http.StatusNoContent, nil
+}
+
+func (s *Server) createSubscription(r *request) (int, interface{}) {
+	topicURN := r.params[0]
+	if _, ok := s.resources["smn/topics"][topicURN]; !ok {
+		return notFound("Topic", topicURN)
+	}
+	for _, field := range []string{"endpoint", "protocol"} {
+		if v, _ := r.body[field].(string); v == "" {
+			return badRequest("%s is required", field)
+		}
+	}
+
+	urn := topicURN + ":" + s.newID()
+	s.add("smn/subscriptions", map[string]interface{}{
+		"id":               urn,
+		"subscription_urn": urn,
+		"topic_urn":        topicURN,
+		"protocol":         r.body["protocol"],
+		"endpoint":         r.body["endpoint"],
+		"remark":           r.body["remark"],
+		"owner":            ProjectID,
+		"status":           0,
+	})
+
+	return http.StatusOK, map[string]interface{}{
+		"request_id":       s.newID(),
+		"subscription_urn": urn,
+	}
+}
+
+// listSubscriptions lists all subscriptions, or those of the topic in the
+// path.
+func (s *Server) listSubscriptions(r *request) (int, interface{}) {
+	subscriptions := []map[string]interface{}{}
+	for _, subscription := range s.list("smn/subscriptions", nil) {
+		if len(r.params) == 0 || subscription["topic_urn"] == r.params[0] {
+			subscriptions = append(subscriptions, subscription)
+		}
+	}
+
+	return http.StatusOK, map[string]interface{}{
+		"request_id":         s.newID(),
+		"subscription_count": len(subscriptions),
+		"subscriptions":      subscriptions,
+	}
+}
+
+func (s *Server) deleteSubscription(r *request) (int, interface{}) {
+	if _, ok := s.resources["smn/subscriptions"][r.params[0]]; !ok {
+		return notFound("Subscription", r.params[0])
+	}
+	delete(s.resources["smn/subscriptions"], r.params[0])
+
+	return http.StatusNoContent, nil
+}
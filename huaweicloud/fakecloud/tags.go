@@ -0,0 +1,110 @@
+package fakecloud
+
+import (
+	"net/http"
+	"sort"
+)
+
+// tagsPaths maps the paths of the resources served by the batch tag APIs of
+// the services to the collections holding the resources.
+var tagsPaths = map[string]string{
+	"/ecs/v1/{project}/cloudservers":    "compute/servers",
+	"/elb/v1.0/{project}/loadbalancers": "elb/loadbalancers",
+	"/kms/v1.0/{project}/kms":           "kms/keys",
+	"/rds/v3/{project}/instances":       "rds/instances",
+	"/smn/v2/{project}/smn_topic":       "smn/topics",
+	"/vpc/v2.0/{project}/publicips":     "vpc/publicips",
+	"/vpc/v2.0/{project}/subnets":       "network/networks",
+	"/vpc/v2.0/{project}/vpcs":          "network/routers",
+}
+
+func (s *Server) registerTags() {
+	for path, collection := range tagsPaths {
+		s.handle("GET", path+"/{id}/tags", s.listTags(collection))
+		s.handle("POST", path+"/{id}/tags/action", s.tagsAction(collection))
+	}
+}
+
+// Tags returns a copy of the tags of the object with the given ID in the
+// collection.
+func (s *Server) Tags(collection, id string) map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return copyObject(s.tagsOf(collection, id))
+}
+
+// SetTag tags the object with the given ID in the collection, e.g. to
+// simulate a system tag added by HuaweiCloud.
+func (s *Server) SetTag(collection, id, key, value string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tagsOf(collection, id)[key] = value
+}
+
+// tagsOf returns the tags of the resource with the given ID in the collection.
+// It must be called with the lock held.
+func (s *Server) tagsOf(collection, id string) map[string]interface{} {
+	key := collection + "/" + id
+	if s.tags[key] == nil {
+		s.tags[key] = make(map[string]interface{})
+	}
+	return s.tags[key]
+}
+
+func (s *Server) listTags(collection string) func(*request) (int, interface{}) {
+	return func(r *request) (int, interface{}) {
+		id := r.params[1]
+		if _, ok := s.resources[collection][id]; !ok {
+			return notFound("Resource", id)
+		}
+
+		var keys []string
+		for k := range s.tagsOf(collection, id) {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		tags := []interface{}{}
+		for _, k := range keys {
+			tags = append(tags, map[string]interface{}{
+				"key":   k,
+				"value": s.tagsOf(collection, id)[k],
+			})
+		}
+		return http.StatusOK, map[string]interface{}{"tags": tags}
+	}
+}
+
+// tagsAction creates or deletes the tags in the body of the request.
+func (s *Server) tagsAction(collection string) func(*request) (int, interface{}) {
+	return func(r *request) (int, interface{}) {
+		id := r.params[1]
+		if _, ok := s.resources[collection][id]; !ok {
+			return notFound("Resource", id)
+		}
+
+		action, _ := r.body["action"].(string)
+		if action != "create" && action != "delete" {
+			return badRequest("Unsupported action %s", action)
+		}
+
+		tags := s.tagsOf(collection, id)
+		raw, _ := r.body["tags"].([]interface{})
+		for _, t := range raw {
+			tag, _ := t.(map[string]interface{})
+			key, _ := tag["key"].(string)
+			if key == "" {
+				return badRequest("Invalid tag key %q", key)
+			}
+			if action == "create" {
+				tags[key] = tag["value"]
+			} else {
+				delete(tags, key)
+			}
+		}
+
+		return http.StatusNoContent, nil
+	}
+}
@@ -0,0 +1,131 @@
+package fakecloud
+
+import (
+	"fmt"
+	"net/http"
+)
+
+const vpcPrefix = "/vpc/v1/" + ProjectID
+
+func (s *Server) registerVPC() {
+	s.handle("POST", vpcPrefix+"/publicips", s.createPublicIP)
+	s.handle("GET", vpcPrefix+"/publicips", s.listCollection("vpc/publicips", "publicips"))
+	s.handle("GET", vpcPrefix+"/publicips/{id}", s.getObject("vpc/publicips", "publicip", "Publicip"))
+	s.handle("PUT", vpcPrefix+"/publicips/{id}", s.updatePublicIP)
+	s.handle("DELETE", vpcPrefix+"/publicips/{id}", s.deletePublicIP)
+
+	s.handle("GET", vpcPrefix+"/bandwidths/{id}", s.getObject("vpc/bandwidths", "bandwidth", "Bandwidth"))
+	s.handle("PUT", vpcPrefix+"/bandwidths/{id}", s.updateBandwidth)
+}
+
+// createPublicIP allocates an EIP along with its dedicated bandwidth.
+func (s *Server) createPublicIP(r *request) (int, interface{}) {
+	ip := r.object("publicip")
+	bw := r.object("bandwidth")
+
+	ipType, _ := ip["type"].(string)
+	if ipType == "" {
+		return badRequest("publicip.type is required")
+	}
+	size, _ := bw["size"].(float64)
+	if size < 1 {
+		return badRequest("bandwidth.size must be at least 1")
+	}
+
+	chargeMode, _ := bw["charge_mode"].(string)
+	if chargeMode == "" {
+		chargeMode = "traffic"
+	}
+	bandwidthID := s.add("vpc/bandwidths", map[string]interface{}{
+		"name":           bw["name"],
+		"size":           size,
+		"share_type":     bw["share_type"],
+		"charge_mode":    chargeMode,
+		"bandwidth_type": "bgp",
+		"tenant_id":      ProjectID,
+	})
+
+	address, _ := ip["ip_address"].(string)
+	if address == "" {
+		address = fmt.Sprintf("198.51.100.%d", s.nextID%250+1)
+	}
+	publicIP := map[string]interface{}{
+		"status":               "DOWN",
+		"type":                 ipType,
+		"public_ip_address":    address,
+		"private_ip_address":   "",
+		"port_id":              "",
+		"tenant_id":            ProjectID,
+		"create_time":          timestamp(),
+		"bandwidth_id":         bandwidthID,
+		"bandwidth_size":       size,
+		"bandwidth_share_type": bw["share_type"],
+	}
+	publicIP["enterprise_project_id"] = defaultEnterpriseProjectID
+	if v, _ := r.body["enterprise_project_id"].(string); v != "" {
+		publicIP["enterprise_project_id"] = v
+	}
+	s.add("vpc/publicips", publicIP)
+
+	return http.StatusOK, map[string]interface{}{"publicip": publicIP}
+}
+
+// updatePublicIP binds an EIP to a port, or unbinds it if port_id is empty.
+func (s *Server) updatePublicIP(r *request) (int, interface{}) {
+	publicIP, ok := s.resources["vpc/publicips"][r.params[0]]
+	if !ok {
+		return notFound("Publicip", r.params[0])
+	}
+
+	portID, _ := r.object("publicip")["port_id"].(string)
+	publicIP["port_id"] = portID
+	publicIP["private_ip_address"] = ""
+	publicIP["status"] = "DOWN"
+	if portID != "" {
+		port, ok := s.resources["network/ports"][portID]
+		if !ok {
+			return notFound("Port", portID)
+		}
+		if ips, _ := port["fixed_ips"].([]interface{}); len(ips) > 0 {
+			publicIP["private_ip_address"] = ips[0].(map[string]interface{})["ip_address"]
+		}
+		publicIP["status"] = "ACTIVE"
+	}
+
+	return http.StatusOK, map[string]interface{}{"publicip": publicIP}
+}
+
+// deletePublicIP releases an EIP and its dedicated bandwidth.
+func (s *Server) deletePublicIP(r *request) (int, interface{}) {
+	publicIP, ok := s.resources["vpc/publicips"][r.params[0]]
+	if !ok {
+		return notFound("Publicip", r.params[0])
+	}
+	if publicIP["bandwidth_share_type"] == "PER" {
+		delete(s.resources["vpc/bandwidths"], publicIP["bandwidth_id"].(string))
+	}
+	delete(s.resources["vpc/publicips"], r.params[0])
+
+	return http.StatusNoContent, nil
+}
+
+func (s *Server) updateBandwidth(r *request) (int, interface{}) {
+	bandwidth, ok := s.resources["vpc/bandwidths"][r.params[0]]
+	if !ok {
+		return notFound("Bandwidth", r.params[0])
+	}
+
+	update := r.object("bandwidth")
+	for _, field := range []string{"name", "size"} {
+		if v, ok := update[field]; ok {
+			bandwidth[field] = v
+		}
+	}
+	for _, publicIP := range s.resources["vpc/publicips"] {
+		if publicIP["bandwidth_id"] == r.params[0] {
+			publicIP["bandwidth_size"] = bandwidth["size"]
+		}
+	}
+
+	return http.StatusOK, map[string]interface{}{"bandwidth": bandwidth}
+}
@@ -0,0 +1,81 @@
+package huaweicloud
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/fakecloud"
+)
+
+const testFakeCloudImageID = "c1d2e3f4-0000-4000-8000-000000000001"
+
+// testFakeCloud starts a fake of the HuaweiCloud APIs seeded with two flavors,
+// an image and a network with a subnet, and returns it along with a Config authenticated
+// against it.
+func testFakeCloud(t *testing.T) (*fakecloud.Server, *Config) {
+	srv := fakecloud.New()
+
+	srv.Add("compute/flavors", map[string]interface{}{
+		"id": "s3.small.1", "name": "s3.small.1", "vcpus": 1, "ram": 1024, "disk": 0,
+	})
+	srv.Add("compute/flavors", map[string]interface{}{
+		"id": "s3.medium.2", "name": "s3.medium.2", "vcpus": 1, "ram": 2048, "disk": 0,
+	})
+	srv.Add("compute/images", map[string]interface{}{
+		"id": testFakeCloudImageID, "name": "Ubuntu 18.04", "status": "ACTIVE",
+	})
+	networkID := srv.Add("network/networks", map[string]interface{}{"name": "fake-network"})
+	srv.Add("network/subnets", map[string]interface{}{
+		"name": "fake-subnet", "network_id": networkID, "cidr": "192.168.0.0/24",
+	})
+
+	config := &Config{
+		IdentityEndpoint: srv.IdentityEndpoint(),
+		Username:         "fake-user",
+		Password:         "fake-password",
+		DomainName:       "fake-domain",
+		TenantName:       fakecloud.Region,
+		Region:           fakecloud.Region,
+
+		skipStateRefreshDelay: true,
+	}
+	if err := config.LoadAndValidate(); err != nil {
+		srv.Close()
+		t.Fatalf("Error authenticating against the fake cloud: %s", err)
+	}
+
+	return srv, config
+}
+
+// testFakeCloudApply creates or updates a resource from its state and raw
+// configuration as terraform apply would, and returns its new state.
+func testFakeCloudApply(t *testing.T, r *schema.Resource, state *terraform.InstanceState,
+	raw map[string]interface{}, meta interface{}) *terraform.InstanceState {
+	c, err := config.NewRawConfig(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diff, err := r.Diff(state, terraform.NewResourceConfig(c))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff == nil {
+		return state
+	}
+
+	newState, err := r.Apply(state, diff, meta)
+	if err != nil {
+		t.Fatalf("Error applying resource: %s", err)
+	}
+	return newState
+}
+
+// testFakeCloudDestroy deletes a resource as terraform destroy would.
+func testFakeCloudDestroy(t *testing.T, r *schema.Resource, state *terraform.InstanceState, meta interface{}) {
+	if _, err := r.Apply(state, &terraform.InstanceDiff{Destroy: true}, meta); err != nil {
+		t.Fatalf("Error destroying resource: %s", err)
+	}
+}
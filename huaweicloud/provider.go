@@ -1,6 +1,8 @@
 package huaweicloud
 
 import (
+	"fmt"
+
 	"github.com/hashicorp/terraform/helper/mutexkv"
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/terraform"
@@ -27,6 +29,14 @@ func Provider() terraform.ResourceProvider {
 				Description: descriptions["secret_key"],
 			},
 
+			"security_token": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				DefaultFunc: schema.EnvDefaultFunc("OS_SECURITY_TOKEN", ""),
+				Description: descriptions["security_token"],
+			},
+
 			"auth_url": &schema.Schema{
 				Type:        schema.TypeString,
 				Optional:    true,
@@ -167,9 +177,83 @@ func Provider() terraform.ResourceProvider {
 				DefaultFunc: schema.EnvDefaultFunc("OS_CLOUD", ""),
 				Description: descriptions["cloud"],
 			},
+
+			"assume_role": &schema.Schema{
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: descriptions["assume_role"],
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"agency_name": &schema.Schema{
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"domain_name": &schema.Schema{
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"domain_id": &schema.Schema{
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"duration": &schema.Schema{
+							Type:         schema.TypeInt,
+							Optional:     true,
+							ValidateFunc: validateAssumeRoleDuration,
+						},
+					},
+				},
+			},
+
+			"default_tags": &schema.Schema{
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: descriptions["default_tags"],
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"tags": &schema.Schema{
+							Type:     schema.TypeMap,
+							Optional: true,
+						},
+					},
+				},
+			},
+
+			"endpoints": &schema.Schema{
+				Type:         schema.TypeMap,
+				Optional:     true,
+				Description:  descriptions["endpoints"],
+				ValidateFunc: validateServiceEndpoints,
+			},
+
+			"max_retries": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("OS_MAX_RETRIES", 5),
+				Description:  descriptions["max_retries"],
+				ValidateFunc: validateNonNegativeInt,
+			},
+
+			"max_requests_per_second": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("OS_MAX_REQUESTS_PER_SECOND", 0),
+				Description:  descriptions["max_requests_per_second"],
+				ValidateFunc: validateNonNegativeInt,
+			},
+
+			"skip_credentials_validation": &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("OS_SKIP_CREDENTIALS_VALIDATION", false),
+				Description: descriptions["skip_credentials_validation"],
+			},
 		},
 
 		DataSourcesMap: map[string]*schema.Resource{
+			"huaweicloud_api_request":            dataSourceAPIRequest(),
 			"huaweicloud_images_image_v2":        dataSourceImagesImageV2(),
 			"huaweicloud_networking_network_v2":  dataSourceNetworkingNetworkV2(),
 			"huaweicloud_networking_subnet_v2":   dataSourceNetworkingSubnetV2(),
@@ -181,6 +265,7 @@ func Provider() terraform.ResourceProvider {
 		},
 
 		ResourcesMap: map[string]*schema.Resource{
+			"huaweicloud_api_resource":                    resourceAPIResource(),
 			"huaweicloud_blockstorage_volume_v2":          resourceBlockStorageVolumeV2(),
 			"huaweicloud_compute_instance_v2":             resourceComputeInstanceV2(),
 			"huaweicloud_compute_keypair_v2":              resourceComputeKeypairV2(),
@@ -189,8 +274,10 @@ func Provider() terraform.ResourceProvider {
 			"huaweicloud_compute_floatingip_v2":           resourceComputeFloatingIPV2(),
 			"huaweicloud_compute_floatingip_associate_v2": resourceComputeFloatingIPAssociateV2(),
 			"huaweicloud_compute_volume_attach_v2":        resourceComputeVolumeAttachV2(),
+			"huaweicloud_compute_interface_attach_v2":     resourceComputeInterfaceAttachV2(),
 			"huaweicloud_dns_recordset_v2":                resourceDNSRecordSetV2(),
 			"huaweicloud_dns_zone_v2":                     resourceDNSZoneV2(),
+			"huaweicloud_ecs_instance_v1":                 resourceEcsInstanceV1(),
 			"huaweicloud_fw_firewall_group_v2":            resourceFWFirewallGroupV2(),
 			"huaweicloud_fw_policy_v2":                    resourceFWPolicyV2(),
 			"huaweicloud_fw_rule_v2":                      resourceFWRuleV2(),
@@ -236,6 +323,8 @@ func init() {
 	descriptions = map[string]string{
 		"auth_url": "The Identity authentication URL.",
 
+		"security_token": "The security token to use with temporary access and secret keys.",
+
 		"region": "The HuaweiCloud region to connect to.",
 
 		"user_name": "Username to login with.",
@@ -273,31 +362,81 @@ func init() {
 			"service (Octavia) instead of the Networking service (Neutron).",
 
 		"cloud": "An entry in a `clouds.yaml` file to use.",
+
+		"assume_role": "The IAM agency of another account to manage resources in.",
+
+		"default_tags": "The tags to add to all taggable resources, unless the resources\n" +
+			"set tags with the same keys.",
+
+		"endpoints": "The custom endpoints to use for services, keyed by service type.\n" +
+			"They take precedence over the endpoints of the service catalog.",
+
+		"max_retries": "The maximum number of retries of API requests failing with\n" +
+			"429 or 5xx response codes, or a reset connection.",
+
+		"max_requests_per_second": "The maximum number of API requests sent per second, 0 for no limit.",
+
+		"skip_credentials_validation": "Skip the authentication when the provider is configured.\n" +
+			"The credentials are then only used on the first API request.",
 	}
 }
 
 func configureProvider(d *schema.ResourceData) (interface{}, error) {
+	endpoints := make(map[string]string)
+	for service, endpoint := range d.Get("endpoints").(map[string]interface{}) {
+		endpoints[service] = endpoint.(string)
+	}
+
+	var assumeRole *AssumeRole
+	if v, ok := d.GetOk("assume_role"); ok {
+		raw := v.([]interface{})[0].(map[string]interface{})
+		assumeRole = &AssumeRole{
+			AgencyName: raw["agency_name"].(string),
+			DomainName: raw["domain_name"].(string),
+			DomainID:   raw["domain_id"].(string),
+			Duration:   raw["duration"].(int),
+		}
+		if assumeRole.DomainName == "" && assumeRole.DomainID == "" {
+			return nil, fmt.Errorf("One of domain_name or domain_id must be set in assume_role")
+		}
+	}
+
+	defaultTags := make(map[string]string)
+	if v, ok := d.GetOk("default_tags"); ok && v.([]interface{})[0] != nil {
+		raw := v.([]interface{})[0].(map[string]interface{})
+		for k, v := range raw["tags"].(map[string]interface{}) {
+			defaultTags[k] = v.(string)
+		}
+	}
+
 	config := Config{
-		AccessKey:        d.Get("access_key").(string),
-		SecretKey:        d.Get("secret_key").(string),
-		CACertFile:       d.Get("cacert_file").(string),
-		ClientCertFile:   d.Get("cert").(string),
-		ClientKeyFile:    d.Get("key").(string),
-		Cloud:            d.Get("cloud").(string),
-		DomainID:         d.Get("domain_id").(string),
-		DomainName:       d.Get("domain_name").(string),
-		EndpointType:     d.Get("endpoint_type").(string),
-		IdentityEndpoint: d.Get("auth_url").(string),
-		Insecure:         d.Get("insecure").(bool),
-		Password:         d.Get("password").(string),
-		Region:           d.Get("region").(string),
-		Swauth:           d.Get("swauth").(bool),
-		Token:            d.Get("token").(string),
-		TenantID:         d.Get("tenant_id").(string),
-		TenantName:       d.Get("tenant_name").(string),
-		Username:         d.Get("user_name").(string),
-		UserID:           d.Get("user_id").(string),
-		useOctavia:       d.Get("use_octavia").(bool),
+		AccessKey:                 d.Get("access_key").(string),
+		AssumeRole:                assumeRole,
+		SecretKey:                 d.Get("secret_key").(string),
+		CACertFile:                d.Get("cacert_file").(string),
+		ClientCertFile:            d.Get("cert").(string),
+		ClientKeyFile:             d.Get("key").(string),
+		Cloud:                     d.Get("cloud").(string),
+		DefaultTags:               defaultTags,
+		DomainID:                  d.Get("domain_id").(string),
+		DomainName:                d.Get("domain_name").(string),
+		EndpointType:              d.Get("endpoint_type").(string),
+		Endpoints:                 endpoints,
+		IdentityEndpoint:          d.Get("auth_url").(string),
+		Insecure:                  d.Get("insecure").(bool),
+		MaxRequestsPerSecond:      d.Get("max_requests_per_second").(int),
+		MaxRetries:                d.Get("max_retries").(int),
+		Password:                  d.Get("password").(string),
+		Region:                    d.Get("region").(string),
+		SecurityToken:             d.Get("security_token").(string),
+		SkipCredentialsValidation: d.Get("skip_credentials_validation").(bool),
+		Swauth:                    d.Get("swauth").(bool),
+		Token:                     d.Get("token").(string),
+		TenantID:                  d.Get("tenant_id").(string),
+		TenantName:                d.Get("tenant_name").(string),
+		Username:                  d.Get("user_name").(string),
+		UserID:                    d.Get("user_id").(string),
+		useOctavia:                d.Get("use_octavia").(bool),
 	}
 
 	if err := config.LoadAndValidate(); err != nil {
@@ -41,7 +41,77 @@ func init() {
 	}
 }
 
+// testAccVCREnv lists the environment variables saved in the cassettes of
+// recorded tests, and restored when replaying them.
+var testAccVCREnv = map[string]*string{
+	"OS_AVAILABILITY_ZONE": &OS_AVAILABILITY_ZONE,
+	"OS_EXTGW_ID":          &OS_EXTGW_ID,
+	"OS_FLAVOR_ID":         &OS_FLAVOR_ID,
+	"OS_FLAVOR_NAME":       &OS_FLAVOR_NAME,
+	"OS_IMAGE_ID":          &OS_IMAGE_ID,
+	"OS_IMAGE_NAME":        &OS_IMAGE_NAME,
+	"OS_NETWORK_ID":        &OS_NETWORK_ID,
+	"OS_POOL_NAME":         &OS_POOL_NAME,
+	"OS_REGION_NAME":       &OS_REGION_NAME,
+	"OS_ACCESS_KEY":        &OS_ACCESS_KEY,
+	"OS_SECRET_KEY":        &OS_SECRET_KEY,
+	"OS_VPC_ID":            &OS_VPC_ID,
+	"OS_TENANT_ID":         &OS_TENANT_ID,
+	"OS_AUTH_URL":          nil,
+	"OS_DOMAIN_NAME":       nil,
+	"OS_TENANT_NAME":       nil,
+	"OS_USERNAME":          nil,
+	"OS_PASSWORD":          nil,
+}
+
+// testAccVCRSecretEnv lists the variables of testAccVCREnv only saved as
+// placeholders.
+var testAccVCRSecretEnv = []string{"OS_ACCESS_KEY", "OS_SECRET_KEY", "OS_PASSWORD"}
+
+// testAccVCR records the API requests of the test into a cassette, or replays
+// them, when HW_VCR_MODE is set to record or replay. The cassette is named
+// after the test.
+func testAccVCR(t *testing.T) {
+	mode := os.Getenv("HW_VCR_MODE")
+	if mode == "" {
+		return
+	}
+
+	os.Setenv("HW_VCR_CASSETTE", t.Name())
+	cassette, err := openVCRCassette(mode, t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if mode == vcrModeReplay {
+		for name, value := range cassette.Env {
+			os.Setenv(name, value)
+			if v := testAccVCREnv[name]; v != nil {
+				*v = value
+			}
+		}
+		return
+	}
+
+	env := make(map[string]string)
+	for name := range testAccVCREnv {
+		if v := os.Getenv(name); v != "" {
+			env[name] = v
+		}
+	}
+	for _, name := range testAccVCRSecretEnv {
+		if _, ok := env[name]; ok {
+			env[name] = redactedValue
+		}
+	}
+	if err := cassette.SetEnv(env); err != nil {
+		t.Fatalf("Error saving the environment of the test: %s", err)
+	}
+}
+
 func testAccPreCheckRequiredEnvVars(t *testing.T) {
+	testAccVCR(t)
+
 	v := os.Getenv("OS_AUTH_URL")
 	if v == "" {
 		t.Fatal("OS_AUTH_URL must be set for acceptance tests")
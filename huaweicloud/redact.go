@@ -0,0 +1,185 @@
+package huaweicloud
+
+import (
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// redactedValue replaces the sensitive values in debug logs.
+const redactedValue = "***"
+
+// redactedFields lists the names of the JSON fields holding sensitive values in
+// the requests and responses of each service.
+var redactedFields = map[string][]string{
+	"identity": {"password", "token", "secret", "securitytoken", "security_token", "secret_key"},
+	"compute":  {"adminPass", "admin_pass", "user_data", "private_key"},
+	"rds":      {"dbrtpd", "dbRtPd", "password"},
+	"kms":      {"plain_text", "plain_text_base64"},
+	"obs":      {"sse_customer_key"},
+	"elb":      {"private_key", "certificate_private_key"},
+	"vpn":      {"psk"},
+}
+
+// redactedPaths lists the fields holding sensitive values under a name which
+// isn't sensitive by itself, such as the ID of the token of a token-auth
+// request. A path matches the end of the dotted path of a field, the indexes of
+// arrays left out.
+var redactedPaths = []string{
+	"identity.token.id",
+	"credential.access",
+}
+
+// redactedQueryParams lists the query string parameters holding sensitive
+// values, such as pre-signed URL signatures.
+var redactedQueryParams = []string{
+	"signature", "x-amz-signature", "x-amz-security-token", "x-amz-credential",
+	"awsaccesskeyid", "temp_url_sig", "token", "securitytoken",
+}
+
+// redactedAWSHeaders lists the headers redacted from the aws-sdk debug logs, in
+// addition to REDACT_HEADERS.
+var redactedAWSHeaders = []string{
+	"x-amz-security-token",
+	"x-amz-server-side-encryption-customer-key",
+	"x-amz-copy-source-server-side-encryption-customer-key",
+}
+
+// Redactor masks sensitive values in the bodies, URLs and headers written to
+// the debug logs. Field and parameter names are matched case-insensitively, at
+// any depth of the JSON documents.
+type Redactor struct {
+	Fields      map[string]bool
+	Paths       []string
+	QueryParams map[string]bool
+	Headers     map[string]bool
+}
+
+// newRedactor returns a Redactor for the built-in sensitive names and the
+// comma separated field names of OS_DEBUG_REDACT_FIELDS.
+func newRedactor() *Redactor {
+	r := &Redactor{
+		Fields:      make(map[string]bool),
+		QueryParams: make(map[string]bool),
+		Headers:     make(map[string]bool),
+	}
+
+	for _, fields := range redactedFields {
+		for _, field := range fields {
+			r.Fields[strings.ToLower(field)] = true
+		}
+	}
+	for _, field := range strings.Split(os.Getenv("OS_DEBUG_REDACT_FIELDS"), ",") {
+		if field = strings.TrimSpace(field); field != "" {
+			r.Fields[strings.ToLower(field)] = true
+		}
+	}
+
+	r.Paths = redactedPaths
+
+	for _, param := range redactedQueryParams {
+		r.QueryParams[param] = true
+	}
+	for _, header := range append(REDACT_HEADERS, redactedAWSHeaders...) {
+		r.Headers[header] = true
+	}
+
+	return r
+}
+
+// RedactJSON masks the values of the sensitive fields of a decoded JSON
+// document, in place. Objects and arrays under a sensitive field are searched
+// instead of being masked, so that their structure remains visible.
+func (r *Redactor) RedactJSON(data interface{}) interface{} {
+	return r.redactJSON(data, "")
+}
+
+func (r *Redactor) redactJSON(data interface{}, path string) interface{} {
+	switch v := data.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			fieldPath := strings.ToLower(key)
+			if path != "" {
+				fieldPath = path + "." + fieldPath
+			}
+
+			switch value.(type) {
+			case map[string]interface{}, []interface{}:
+				v[key] = r.redactJSON(value, fieldPath)
+			default:
+				if value != nil && (r.Fields[strings.ToLower(key)] || r.sensitivePath(fieldPath)) {
+					v[key] = redactedValue
+				}
+			}
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = r.redactJSON(value, path)
+		}
+	}
+
+	return data
+}
+
+// sensitivePath returns true if the dotted path of a field ends with one of the
+// sensitive paths.
+func (r *Redactor) sensitivePath(path string) bool {
+	for _, p := range r.Paths {
+		if path == p || strings.HasSuffix(path, "."+p) {
+			return true
+		}
+	}
+	return false
+}
+
+// RedactURL returns the URL with the values of the sensitive query string
+// parameters masked.
+func (r *Redactor) RedactURL(u *url.URL) string {
+	if u.RawQuery == "" {
+		return u.String()
+	}
+
+	redacted := *u
+	redacted.RawQuery = r.redactQuery(u.RawQuery)
+	return redacted.String()
+}
+
+// redactQuery masks the values of the sensitive parameters of a query string,
+// keeping the order of the parameters.
+func (r *Redactor) redactQuery(query string) string {
+	params := strings.Split(query, "&")
+	for i, param := range params {
+		parts := strings.SplitN(param, "=", 2)
+		name, err := url.QueryUnescape(parts[0])
+		if err != nil {
+			name = parts[0]
+		}
+		if len(parts) == 2 && r.QueryParams[strings.ToLower(name)] {
+			params[i] = parts[0] + "=" + redactedValue
+		}
+	}
+	return strings.Join(params, "&")
+}
+
+var (
+	redactHeaderLine = regexp.MustCompile(`^([A-Za-z0-9-]+):\s?(.*)$`)
+	redactQueryPart  = regexp.MustCompile(`\?[^\s#]*`)
+)
+
+// RedactText masks the sensitive headers and query string parameters of a
+// dumped HTTP request or response, as logged by the aws-sdk.
+func (r *Redactor) RedactText(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		trimmed := strings.TrimSuffix(line, "\r")
+		if m := redactHeaderLine.FindStringSubmatch(trimmed); m != nil && r.Headers[strings.ToLower(m[1])] {
+			lines[i] = m[1] + ": " + redactedValue + line[len(trimmed):]
+			continue
+		}
+		lines[i] = redactQueryPart.ReplaceAllStringFunc(line, func(query string) string {
+			return "?" + r.redactQuery(query[1:])
+		})
+	}
+	return strings.Join(lines, "\n")
+}
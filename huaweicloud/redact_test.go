@@ -0,0 +1,80 @@
+package huaweicloud
+
+import (
+	"encoding/json"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestRedactorJSON(t *testing.T) {
+	os.Setenv("OS_DEBUG_REDACT_FIELDS", "custom_secret")
+	defer os.Unsetenv("OS_DEBUG_REDACT_FIELDS")
+
+	lrt := &LogRoundTripper{Redactor: newRedactor()}
+	body := `{
+		"auth": {"identity": {"password": {"user": {"name": "admin", "password": "pw"}}}},
+		"server": {"name": "web", "adminPass": "pw", "user_data": "IyEvYmluL3No"},
+		"instances": [{"dbrtpd": "pw", "port": 3306}],
+		"plain_text": "key",
+		"custom_secret": "value"
+	}`
+
+	formatted := lrt.formatJSON([]byte(body))
+	var data map[string]interface{}
+	if err := json.Unmarshal([]byte(formatted), &data); err != nil {
+		t.Fatalf("Error parsing formatted JSON: %s", err)
+	}
+
+	for _, secret := range []string{`"pw"`, "IyEvYmluL3No", `"key"`, `"value"`} {
+		if strings.Contains(formatted, secret) {
+			t.Fatalf("Expected %s to be redacted from:\n%s", secret, formatted)
+		}
+	}
+	for _, visible := range []string{`"admin"`, `"web"`, "3306"} {
+		if !strings.Contains(formatted, visible) {
+			t.Fatalf("Expected %s to remain in:\n%s", visible, formatted)
+		}
+	}
+}
+
+func TestRedactorJSONPaths(t *testing.T) {
+	lrt := &LogRoundTripper{Redactor: newRedactor()}
+	body := `{
+		"auth": {"identity": {"methods": ["token"], "token": {"id": "token-id"}}},
+		"credential": {"access": "access-key", "secret": "secret-key", "expires_at": "2020-01-01"},
+		"server": {"id": "server-id"}
+	}`
+
+	formatted := lrt.formatJSON([]byte(body))
+	for _, secret := range []string{"token-id", "access-key", "secret-key"} {
+		if strings.Contains(formatted, secret) {
+			t.Fatalf("Expected %s to be redacted from:\n%s", secret, formatted)
+		}
+	}
+	for _, visible := range []string{`"token"`, "2020-01-01", "server-id"} {
+		if !strings.Contains(formatted, visible) {
+			t.Fatalf("Expected %s to remain in:\n%s", visible, formatted)
+		}
+	}
+}
+
+func TestRedactorURLAndText(t *testing.T) {
+	r := newRedactor()
+
+	u, _ := url.Parse("https://obs.example.com/bucket/key?X-Amz-Signature=abc&versionId=1")
+	if v := r.RedactURL(u); v != "https://obs.example.com/bucket/key?X-Amz-Signature=***&versionId=1" {
+		t.Fatalf("Unexpected redacted URL: %s", v)
+	}
+
+	dump := "PUT /bucket/key?Signature=abc HTTP/1.1\r\n" +
+		"Host: obs.example.com\r\n" +
+		"X-Amz-Server-Side-Encryption-Customer-Key: c2VjcmV0\r\n"
+	expected := "PUT /bucket/key?Signature=*** HTTP/1.1\r\n" +
+		"Host: obs.example.com\r\n" +
+		"X-Amz-Server-Side-Encryption-Customer-Key: ***\r\n"
+	if v := r.RedactText(dump); v != expected {
+		t.Fatalf("Unexpected redacted text:\n%q", v)
+	}
+}
@@ -0,0 +1,211 @@
+package huaweicloud
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func resourceAPIResource() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceAPIResourceCreate,
+		Read:   resourceAPIResourceRead,
+		Update: resourceAPIResourceUpdate,
+		Delete: resourceAPIResourceDelete,
+
+		Schema: map[string]*schema.Schema{
+			"region": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+			"service_type": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"create_path": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"create_method": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+				Default:  "POST",
+			},
+			"create_body": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validateJsonString,
+			},
+			"id_path": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"read_path": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"update_path": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"update_method": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "PUT",
+			},
+			"update_body": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateJsonString,
+			},
+			"delete_path": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"delete_method": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "DELETE",
+			},
+			"headers": &schema.Schema{
+				Type:      schema.TypeMap,
+				Optional:  true,
+				Sensitive: true,
+			},
+			"result_paths": &schema.Schema{
+				Type:     schema.TypeMap,
+				Optional: true,
+			},
+			"response_body": &schema.Schema{
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
+			},
+			"results": &schema.Schema{
+				Type:     schema.TypeMap,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceAPIResourceCreate(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+	client, err := apiServiceClientFromSchema(d, config)
+	if err != nil {
+		return err
+	}
+
+	url, err := expandAPIPath(client, config, d.Get("create_path").(string), GetRegion(d, config), "")
+	if err != nil {
+		return err
+	}
+
+	method := d.Get("create_method").(string)
+	resp, err := sendAPIRequest(client, method, url, d.Get("create_body").(string), d.Get("headers").(map[string]interface{}))
+	if err != nil {
+		return fmt.Errorf("Error creating API resource with %s %s: %s", method, url, err)
+	}
+
+	idPath := d.Get("id_path").(string)
+	id, ok := jsonPathValue(resp.Result, idPath)
+	if !ok || id == nil || jsonValueString(id) == "" {
+		return fmt.Errorf("Error creating API resource: no ID at %q in the response: %s", idPath, resp.Body)
+	}
+
+	d.SetId(jsonValueString(id))
+	log.Printf("[INFO] API resource ID: %s", d.Id())
+
+	return resourceAPIResourceRead(d, meta)
+}
+
+func resourceAPIResourceRead(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+	client, err := apiServiceClientFromSchema(d, config)
+	if err != nil {
+		return err
+	}
+
+	region := GetRegion(d, config)
+	url, err := expandAPIPath(client, config, d.Get("read_path").(string), region, d.Id())
+	if err != nil {
+		return err
+	}
+
+	resp, err := sendAPIRequest(client, "GET", url, "", d.Get("headers").(map[string]interface{}))
+	if err != nil {
+		return CheckDeleted(d, err, "API resource")
+	}
+	log.Printf("[DEBUG] Retrieved API resource %s: %s", d.Id(), resp.Body)
+
+	d.Set("region", region)
+	d.Set("response_body", resp.Body)
+	d.Set("results", flattenAPIResults(d, resp))
+
+	return nil
+}
+
+func resourceAPIResourceUpdate(d *schema.ResourceData, meta interface{}) error {
+	if !d.HasChange("update_body") {
+		return resourceAPIResourceRead(d, meta)
+	}
+
+	path := d.Get("update_path").(string)
+	if path == "" {
+		return fmt.Errorf("Error updating API resource %s: update_body changed but update_path isn't set", d.Id())
+	}
+
+	config := meta.(*Config)
+	client, err := apiServiceClientFromSchema(d, config)
+	if err != nil {
+		return err
+	}
+
+	url, err := expandAPIPath(client, config, path, GetRegion(d, config), d.Id())
+	if err != nil {
+		return err
+	}
+
+	method := d.Get("update_method").(string)
+	_, err = sendAPIRequest(client, method, url, d.Get("update_body").(string), d.Get("headers").(map[string]interface{}))
+	if err != nil {
+		return fmt.Errorf("Error updating API resource %s with %s %s: %s", d.Id(), method, url, err)
+	}
+
+	return resourceAPIResourceRead(d, meta)
+}
+
+func resourceAPIResourceDelete(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+	client, err := apiServiceClientFromSchema(d, config)
+	if err != nil {
+		return err
+	}
+
+	path := d.Get("delete_path").(string)
+	if path == "" {
+		path = d.Get("read_path").(string)
+	}
+	url, err := expandAPIPath(client, config, path, GetRegion(d, config), d.Id())
+	if err != nil {
+		return err
+	}
+
+	method := d.Get("delete_method").(string)
+	_, err = sendAPIRequest(client, method, url, "", d.Get("headers").(map[string]interface{}))
+	if err != nil {
+		return CheckDeleted(d, err, "Error deleting API resource")
+	}
+
+	d.SetId("")
+	return nil
+}
@@ -0,0 +1,152 @@
+package huaweicloud
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/fakecloud"
+)
+
+func TestFakeCloudAPIResource(t *testing.T) {
+	t.Parallel()
+
+	srv, config := testFakeCloud(t)
+	defer srv.Close()
+
+	r := resourceAPIResource()
+	raw := map[string]interface{}{
+		"service_type": "network",
+		"create_path":  "v2.0/security-groups",
+		"create_body":  `{"security_group": {"name": "sg_1", "description": "foo"}}`,
+		"id_path":      "security_group.id",
+		"read_path":    "v2.0/security-groups/{id}",
+		"update_path":  "v2.0/security-groups/{id}",
+		"update_body":  `{"security_group": {"description": "foo"}}`,
+		"result_paths": map[string]interface{}{
+			"name":        "security_group.name",
+			"description": "security_group.description",
+		},
+	}
+	state := testFakeCloudApply(t, r, nil, raw, config)
+	if v := state.Attributes["results.name"]; v != "sg_1" {
+		t.Fatalf("Expected the name to be read back, got %s", v)
+	}
+
+	raw["update_body"] = `{"security_group": {"description": "bar"}}`
+	state = testFakeCloudApply(t, r, state, raw, config)
+	if v := state.Attributes["results.description"]; v != "bar" {
+		t.Fatalf("Expected the description to be updated, got %s", v)
+	}
+
+	// Paths starting with a slash are relative to the host of the endpoint.
+	d := dataSourceAPIRequest().TestResourceData()
+	d.Set("service_type", "network")
+	d.Set("path", "/vpc/v2.0/security-groups/"+state.ID)
+	d.Set("result_paths", map[string]interface{}{"name": "security_group.name"})
+	if err := dataSourceAPIRequestRead(d, config); err != nil {
+		t.Fatalf("Error sending API request: %s", err)
+	}
+	if v := d.Get("status_code").(int); v != 200 {
+		t.Fatalf("Expected status code 200, got %d", v)
+	}
+	if v := d.Get("results.name").(string); v != "sg_1" {
+		t.Fatalf("Expected the name in the results, got %s", v)
+	}
+
+	testFakeCloudDestroy(t, r, state, config)
+	if _, ok := srv.Get("network/security-groups", state.ID); ok {
+		t.Fatalf("Security group %s still exists", state.ID)
+	}
+}
+
+func TestFakeCloudAPIPathDomainID(t *testing.T) {
+	t.Parallel()
+
+	srv, config := testFakeCloud(t)
+	defer srv.Close()
+
+	// The domain isn't configured, it's the domain of the user of the token.
+	client, err := config.apiServiceClient("identity", fakecloud.Region)
+	if err != nil {
+		t.Fatalf("Error creating identity client: %s", err)
+	}
+	url, err := expandAPIPath(client, config, "/v3/domains/{domain_id}", fakecloud.Region, "")
+	if err != nil {
+		t.Fatalf("Error expanding path: %s", err)
+	}
+	if !strings.HasSuffix(url, "/v3/domains/"+fakecloud.DomainID) {
+		t.Fatalf("Unexpected URL: %s", url)
+	}
+
+	// The ID is escaped.
+	url, err = expandAPIPath(client, config, "v3/objects/{id}", fakecloud.Region, "a/b c")
+	if err != nil {
+		t.Fatalf("Error expanding path: %s", err)
+	}
+	if !strings.HasSuffix(url, "/v3/objects/a%2Fb%20c") {
+		t.Fatalf("Unexpected URL: %s", url)
+	}
+
+	// The domain is looked up once.
+	if _, err := expandAPIPath(client, config, "/v3/domains/{domain_id}", fakecloud.Region, ""); err != nil {
+		t.Fatalf("Error expanding path: %s", err)
+	}
+	var lookups int
+	for _, req := range srv.Requests() {
+		if req == "GET /v3/auth/tokens" {
+			lookups++
+		}
+	}
+	if lookups != 1 {
+		t.Fatalf("Expected a single lookup of the token, got %d", lookups)
+	}
+
+	// Without a token, the domain must be configured.
+	noToken := &Config{Swauth: true}
+	if _, err := noToken.domainID(); err == nil {
+		t.Fatal("Expected an error when the domain ID is unknown")
+	}
+}
+
+func TestAPIResourceDiff(t *testing.T) {
+	r := resourceAPIResource()
+	state := &terraform.InstanceState{
+		ID: "sg_1",
+		Attributes: map[string]string{
+			"id":            "sg_1",
+			"service_type":  "network",
+			"create_path":   "v2.0/security-groups",
+			"create_method": "POST",
+			"id_path":       "security_group.id",
+			"read_path":     "v2.0/security-groups/{id}",
+			"update_method": "PUT",
+			"delete_method": "DELETE",
+			"region":        fakecloud.Region,
+		},
+	}
+
+	c, err := config.NewRawConfig(map[string]interface{}{
+		"service_type": "network",
+		"create_path":  "v2.0/security-groups",
+		"id_path":      "security_group.id",
+		"read_path":    "v2.0/security-groups/{id}/",
+		"headers":      map[string]interface{}{"X-Secret": "secret"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	diff, err := r.Diff(state, terraform.NewResourceConfig(c))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A resource read from another path may be another resource.
+	if diff == nil || !diff.RequiresNew() {
+		t.Fatalf("Expected a change of read_path to replace the resource, got %v", diff)
+	}
+	if a := diff.Attributes["headers.X-Secret"]; a == nil || !a.Sensitive {
+		t.Fatalf("Expected the headers to be sensitive, got %#v", a)
+	}
+}
@@ -36,6 +36,11 @@ func resourceBlockStorageVolumeV2() *schema.Resource {
 				Computed: true,
 				ForceNew: true,
 			},
+			"project_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
 
 			"size": &schema.Schema{
 				Type:     schema.TypeInt,
@@ -64,6 +69,9 @@ func resourceBlockStorageVolumeV2() *schema.Resource {
 				ForceNew: false,
 				Computed: true,
 			},
+			"tags":                tagsSchema(),
+			"tags_all":            tagsAllSchema(),
+			"deletion_protection": deletionProtectionSchema(),
 			"snapshot_id": &schema.Schema{
 				Type:     schema.TypeString,
 				Optional: true,
@@ -121,7 +129,7 @@ func resourceBlockStorageVolumeV2() *schema.Resource {
 }
 
 func resourceBlockStorageVolumeV2Create(d *schema.ResourceData, meta interface{}) error {
-	config := meta.(*Config)
+	config := GetProjectConfig(d, meta.(*Config))
 	blockStorageClient, err := config.blockStorageV2Client(GetRegion(d, config))
 	if err != nil {
 		return fmt.Errorf("Error creating HuaweiCloud block storage client: %s", err)
@@ -172,11 +180,19 @@ func resourceBlockStorageVolumeV2Create(d *schema.ResourceData, meta interface{}
 	// Store the ID now
 	d.SetId(v.ID)
 
+	evsClient, err := config.evsV2Client(GetRegion(d, config))
+	if err != nil {
+		return fmt.Errorf("Error creating HuaweiCloud EVS client: %s", err)
+	}
+	if err := updateResourceTags(evsClient, d, config, evsClient.ServiceURL("cloudvolumes", d.Id(), "tags")); err != nil {
+		return err
+	}
+
 	return resourceBlockStorageVolumeV2Read(d, meta)
 }
 
 func resourceBlockStorageVolumeV2Read(d *schema.ResourceData, meta interface{}) error {
-	config := meta.(*Config)
+	config := GetProjectConfig(d, meta.(*Config))
 	blockStorageClient, err := config.blockStorageV2Client(GetRegion(d, config))
 	if err != nil {
 		return fmt.Errorf("Error creating HuaweiCloud block storage client: %s", err)
@@ -223,11 +239,19 @@ OUTER:
 	}
 	d.Set("attachment", attachments)
 
+	evsClient, err := config.evsV2Client(GetRegion(d, config))
+	if err != nil {
+		return fmt.Errorf("Error creating HuaweiCloud EVS client: %s", err)
+	}
+	if err := readResourceTags(evsClient, d, config, evsClient.ServiceURL("cloudvolumes", d.Id(), "tags")); err != nil {
+		return err
+	}
+
 	return nil
 }
 
 func resourceBlockStorageVolumeV2Update(d *schema.ResourceData, meta interface{}) error {
-	config := meta.(*Config)
+	config := GetProjectConfig(d, meta.(*Config))
 	blockStorageClient, err := config.blockStorageV2Client(GetRegion(d, config))
 	if err != nil {
 		return fmt.Errorf("Error creating HuaweiCloud block storage client: %s", err)
@@ -247,11 +271,25 @@ func resourceBlockStorageVolumeV2Update(d *schema.ResourceData, meta interface{}
 		return fmt.Errorf("Error updating HuaweiCloud volume: %s", err)
 	}
 
+	if d.HasChange("tags") {
+		evsClient, err := config.evsV2Client(GetRegion(d, config))
+		if err != nil {
+			return fmt.Errorf("Error creating HuaweiCloud EVS client: %s", err)
+		}
+		if err := updateResourceTags(evsClient, d, config, evsClient.ServiceURL("cloudvolumes", d.Id(), "tags")); err != nil {
+			return err
+		}
+	}
+
 	return resourceBlockStorageVolumeV2Read(d, meta)
 }
 
 func resourceBlockStorageVolumeV2Delete(d *schema.ResourceData, meta interface{}) error {
-	config := meta.(*Config)
+	if err := checkDeletionProtection(d, "volume"); err != nil {
+		return err
+	}
+
+	config := GetProjectConfig(d, meta.(*Config))
 	blockStorageClient, err := config.blockStorageV2Client(GetRegion(d, config))
 	if err != nil {
 		return fmt.Errorf("Error creating HuaweiCloud block storage client: %s", err)
@@ -28,6 +28,11 @@ func resourceComputeFloatingIPAssociateV2() *schema.Resource {
 				Computed: true,
 				ForceNew: true,
 			},
+			"project_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
 
 			"floating_ip": &schema.Schema{
 				Type:     schema.TypeString,
@@ -50,7 +55,7 @@ func resourceComputeFloatingIPAssociateV2() *schema.Resource {
 }
 
 func resourceComputeFloatingIPAssociateV2Create(d *schema.ResourceData, meta interface{}) error {
-	config := meta.(*Config)
+	config := GetProjectConfig(d, meta.(*Config))
 	computeClient, err := config.computeV2Client(GetRegion(d, config))
 	if err != nil {
 		return fmt.Errorf("Error creating HuaweiCloud compute client: %s", err)
@@ -84,7 +89,7 @@ func resourceComputeFloatingIPAssociateV2Create(d *schema.ResourceData, meta int
 }
 
 func resourceComputeFloatingIPAssociateV2Read(d *schema.ResourceData, meta interface{}) error {
-	config := meta.(*Config)
+	config := GetProjectConfig(d, meta.(*Config))
 	computeClient, err := config.computeV2Client(GetRegion(d, config))
 	if err != nil {
 		return fmt.Errorf("Error creating HuaweiCloud compute client: %s", err)
@@ -155,7 +160,7 @@ func resourceComputeFloatingIPAssociateV2Read(d *schema.ResourceData, meta inter
 }
 
 func resourceComputeFloatingIPAssociateV2Delete(d *schema.ResourceData, meta interface{}) error {
-	config := meta.(*Config)
+	config := GetProjectConfig(d, meta.(*Config))
 	computeClient, err := config.computeV2Client(GetRegion(d, config))
 	if err != nil {
 		return fmt.Errorf("Error creating HuaweiCloud compute client: %s", err)
@@ -28,6 +28,11 @@ func resourceComputeFloatingIPV2() *schema.Resource {
 				Computed: true,
 				ForceNew: true,
 			},
+			"project_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
 
 			"pool": &schema.Schema{
 				Type:        schema.TypeString,
@@ -55,7 +60,7 @@ func resourceComputeFloatingIPV2() *schema.Resource {
 }
 
 func resourceComputeFloatingIPV2Create(d *schema.ResourceData, meta interface{}) error {
-	config := meta.(*Config)
+	config := GetProjectConfig(d, meta.(*Config))
 	computeClient, err := config.computeV2Client(GetRegion(d, config))
 	if err != nil {
 		return fmt.Errorf("Error creating HuaweiCloud compute client: %s", err)
@@ -76,7 +81,7 @@ func resourceComputeFloatingIPV2Create(d *schema.ResourceData, meta interface{})
 }
 
 func resourceComputeFloatingIPV2Read(d *schema.ResourceData, meta interface{}) error {
-	config := meta.(*Config)
+	config := GetProjectConfig(d, meta.(*Config))
 	computeClient, err := config.computeV2Client(GetRegion(d, config))
 	if err != nil {
 		return fmt.Errorf("Error creating HuaweiCloud compute client: %s", err)
@@ -117,7 +122,7 @@ func FloatingIPV2StateRefreshFunc(computeClient *gophercloud.ServiceClient, d *s
 }
 
 func resourceComputeFloatingIPV2Delete(d *schema.ResourceData, meta interface{}) error {
-	config := meta.(*Config)
+	config := GetProjectConfig(d, meta.(*Config))
 	computeClient, err := config.computeV2Client(GetRegion(d, config))
 	if err != nil {
 		return fmt.Errorf("Error creating HuaweiCloud compute client: %s", err)
@@ -13,6 +13,7 @@ import (
 	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/availabilityzones"
 	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/bootfromvolume"
 	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/keypairs"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/lockunlock"
 	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/schedulerhints"
 	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/secgroups"
 	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/startstop"
@@ -30,6 +31,9 @@ func resourceComputeInstanceV2() *schema.Resource {
 		Read:   resourceComputeInstanceV2Read,
 		Update: resourceComputeInstanceV2Update,
 		Delete: resourceComputeInstanceV2Delete,
+		Importer: &schema.ResourceImporter{
+			State: resourceComputeInstanceV2ImportState,
+		},
 
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(30 * time.Minute),
@@ -44,6 +48,15 @@ func resourceComputeInstanceV2() *schema.Resource {
 				Computed: true,
 				ForceNew: true,
 			},
+			"project_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+			"enterprise_project_id": enterpriseProjectIDSchema(),
+			"tags":                  tagsSchema(),
+			"tags_all":              tagsAllSchema(),
+			"deletion_protection":   deletionProtectionSchema(),
 
 			"name": &schema.Schema{
 				Type:     schema.TypeString,
@@ -62,6 +75,14 @@ func resourceComputeInstanceV2() *schema.Resource {
 				ForceNew: true,
 				Computed: true,
 			},
+			// The vendored helper/schema can't make the image changes conditionally
+			// ForceNew, so the image to rebuild the server with in place is a
+			// separate argument.
+			"rebuild_image_id": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"block_device"},
+			},
 			"flavor_id": &schema.Schema{
 				Type:        schema.TypeString,
 				Optional:    true,
@@ -113,32 +134,27 @@ func resourceComputeInstanceV2() *schema.Resource {
 			"network": &schema.Schema{
 				Type:     schema.TypeList,
 				Optional: true,
-				ForceNew: true,
 				Computed: true,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"uuid": &schema.Schema{
 							Type:     schema.TypeString,
 							Optional: true,
-							ForceNew: true,
 							Computed: true,
 						},
 						"name": &schema.Schema{
 							Type:     schema.TypeString,
 							Optional: true,
-							ForceNew: true,
 							Computed: true,
 						},
 						"port": &schema.Schema{
 							Type:     schema.TypeString,
 							Optional: true,
-							ForceNew: true,
 							Computed: true,
 						},
 						"fixed_ip_v4": &schema.Schema{
 							Type:     schema.TypeString,
 							Optional: true,
-							ForceNew: true,
 							Computed: true,
 						},
 						"fixed_ip_v6": &schema.Schema{
@@ -329,6 +345,14 @@ func resourceComputeInstanceV2() *schema.Resource {
 				Optional: true,
 				Default:  false,
 			},
+			"power_state": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ValidateFunc: func(v interface{}, k string) (ws []string, errors []error) {
+					return ValidateStringList(v, k, []string{"active", "shutoff", "paused", "suspended", "locked"})
+				},
+			},
 			"all_metadata": &schema.Schema{
 				Type:     schema.TypeMap,
 				Computed: true,
@@ -338,7 +362,7 @@ func resourceComputeInstanceV2() *schema.Resource {
 }
 
 func resourceComputeInstanceV2Create(d *schema.ResourceData, meta interface{}) error {
-	config := meta.(*Config)
+	config := GetProjectConfig(d, meta.(*Config))
 	computeClient, err := config.computeV2Client(GetRegion(d, config))
 	if err != nil {
 		return fmt.Errorf("Error creating HuaweiCloud compute client: %s", err)
@@ -355,6 +379,13 @@ func resourceComputeInstanceV2Create(d *schema.ResourceData, meta interface{}) e
 		return err
 	}
 
+	// The image to rebuild the server with takes precedence, image_id keeps
+	// the image the server would have been created with.
+	if v := d.Get("rebuild_image_id").(string); v != "" {
+		d.Set("image_id", imageId)
+		imageId = v
+	}
+
 	flavorId, err := getFlavorID(computeClient, d)
 	if err != nil {
 		return err
@@ -451,7 +482,7 @@ func resourceComputeInstanceV2Create(d *schema.ResourceData, meta interface{}) e
 		Target:     []string{"ACTIVE"},
 		Refresh:    ServerV2StateRefreshFunc(computeClient, server.ID),
 		Timeout:    d.Timeout(schema.TimeoutCreate),
-		Delay:      10 * time.Second,
+		Delay:      config.stateRefreshDelay(10 * time.Second),
 		MinTimeout: 3 * time.Second,
 	}
 
@@ -462,17 +493,36 @@ func resourceComputeInstanceV2Create(d *schema.ResourceData, meta interface{}) e
 			server.ID, err)
 	}
 
+	if err := migrateEnterpriseProjectOnCreate(d, config, epsResourceTypeECS); err != nil {
+		return err
+	}
+
+	computeV1Client, err := config.computeV1Client(GetRegion(d, config))
+	if err != nil {
+		return fmt.Errorf("Error creating HuaweiCloud compute v1 client: %s", err)
+	}
+	if err := updateResourceTags(computeV1Client, d, config, computeV1Client.ServiceURL("cloudservers", d.Id(), "tags")); err != nil {
+		return err
+	}
+
+	if v, ok := d.GetOk("power_state"); ok && v.(string) != "active" {
+		if err := setInstancePowerState(config, computeClient, d.Id(), v.(string), d.Timeout(schema.TimeoutCreate)); err != nil {
+			return err
+		}
+	}
+
 	return resourceComputeInstanceV2Read(d, meta)
 }
 
 func resourceComputeInstanceV2Read(d *schema.ResourceData, meta interface{}) error {
-	config := meta.(*Config)
+	config := GetProjectConfig(d, meta.(*Config))
 	computeClient, err := config.computeV2Client(GetRegion(d, config))
 	if err != nil {
 		return fmt.Errorf("Error creating HuaweiCloud compute client: %s", err)
 	}
 
-	server, err := servers.Get(computeClient, d.Id()).Extract()
+	result := servers.Get(computeClient, d.Id())
+	server, err := result.Extract()
 	if err != nil {
 		return CheckDeleted(d, err, "server")
 	}
@@ -481,6 +531,27 @@ func resourceComputeInstanceV2Read(d *schema.ResourceData, meta interface{}) err
 
 	d.Set("name", server.Name)
 
+	// Nova doesn't return the enterprise project of a server, the ECS API does.
+	ecsClient, err := config.computeV1Client(GetRegion(d, config))
+	if err != nil {
+		return fmt.Errorf("Error creating HuaweiCloud compute v1 client: %s", err)
+	}
+	ecsServer, err := getECSInstance(ecsClient, d.Id())
+	if err != nil {
+		return fmt.Errorf("Error retrieving the enterprise project of HuaweiCloud server %s: %s", d.Id(), err)
+	}
+	if ecsServer.EnterpriseProjectID != "" {
+		d.Set("enterprise_project_id", ecsServer.EnterpriseProjectID)
+	}
+
+	var serverWithLock struct {
+		Locked bool `json:"locked"`
+	}
+	if err := result.ExtractInto(&serverWithLock); err != nil {
+		return fmt.Errorf("Error retrieving HuaweiCloud server %s: %s", d.Id(), err)
+	}
+	d.Set("power_state", flattenInstancePowerState(server.Status, serverWithLock.Locked))
+
 	// Get the instance network and address information
 	networks, err := flattenInstanceNetworks(d, meta)
 	if err != nil {
@@ -545,8 +616,12 @@ func resourceComputeInstanceV2Read(d *schema.ResourceData, meta interface{}) err
 	}
 	d.Set("flavor_name", flavor.Name)
 
-	// Set the instance's image information appropriately
-	if err := setImageInformation(computeClient, server, d); err != nil {
+	// Set the instance's image information appropriately. The image of a
+	// rebuilt server is the image it was rebuilt with, image_id and image_name
+	// keep the image it was created with.
+	if _, ok := d.GetOk("rebuild_image_id"); ok {
+		d.Set("rebuild_image_id", server.Image["id"])
+	} else if err := setImageInformation(computeClient, server, d); err != nil {
 		return err
 	}
 
@@ -565,6 +640,14 @@ func resourceComputeInstanceV2Read(d *schema.ResourceData, meta interface{}) err
 	// Set the availability zone
 	d.Set("availability_zone", serverWithAZ.AvailabilityZone)
 
+	computeV1Client, err := config.computeV1Client(GetRegion(d, config))
+	if err != nil {
+		return fmt.Errorf("Error creating HuaweiCloud compute v1 client: %s", err)
+	}
+	if err := readResourceTags(computeV1Client, d, config, computeV1Client.ServiceURL("cloudservers", d.Id(), "tags")); err != nil {
+		return err
+	}
+
 	// Set the region
 	d.Set("region", GetRegion(d, config))
 
@@ -572,7 +655,7 @@ func resourceComputeInstanceV2Read(d *schema.ResourceData, meta interface{}) err
 }
 
 func resourceComputeInstanceV2Update(d *schema.ResourceData, meta interface{}) error {
-	config := meta.(*Config)
+	config := GetProjectConfig(d, meta.(*Config))
 	computeClient, err := config.computeV2Client(GetRegion(d, config))
 	if err != nil {
 		return fmt.Errorf("Error creating HuaweiCloud compute client: %s", err)
@@ -590,6 +673,12 @@ func resourceComputeInstanceV2Update(d *schema.ResourceData, meta interface{}) e
 		}
 	}
 
+	if d.HasChange("rebuild_image_id") {
+		if err := resourceComputeInstanceV2Rebuild(d, config, computeClient); err != nil {
+			return err
+		}
+	}
+
 	if d.HasChange("metadata") {
 		oldMetadata, newMetadata := d.GetChange("metadata")
 		var metadataToDelete []string
@@ -661,6 +750,12 @@ func resourceComputeInstanceV2Update(d *schema.ResourceData, meta interface{}) e
 		}
 	}
 
+	if d.HasChange("network") {
+		if err := updateInstanceNetworks(d, meta, computeClient); err != nil {
+			return err
+		}
+	}
+
 	if d.HasChange("admin_pass") {
 		if newPwd, ok := d.Get("admin_pass").(string); ok {
 			err := servers.ChangeAdminPassword(computeClient, d.Id(), newPwd).ExtractErr()
@@ -700,7 +795,7 @@ func resourceComputeInstanceV2Update(d *schema.ResourceData, meta interface{}) e
 			Target:     []string{"VERIFY_RESIZE"},
 			Refresh:    ServerV2StateRefreshFunc(computeClient, d.Id()),
 			Timeout:    d.Timeout(schema.TimeoutUpdate),
-			Delay:      10 * time.Second,
+			Delay:      config.stateRefreshDelay(10 * time.Second),
 			MinTimeout: 3 * time.Second,
 		}
 
@@ -721,7 +816,7 @@ func resourceComputeInstanceV2Update(d *schema.ResourceData, meta interface{}) e
 			Target:     []string{"ACTIVE"},
 			Refresh:    ServerV2StateRefreshFunc(computeClient, d.Id()),
 			Timeout:    d.Timeout(schema.TimeoutUpdate),
-			Delay:      10 * time.Second,
+			Delay:      config.stateRefreshDelay(10 * time.Second),
 			MinTimeout: 3 * time.Second,
 		}
 
@@ -731,16 +826,94 @@ func resourceComputeInstanceV2Update(d *schema.ResourceData, meta interface{}) e
 		}
 	}
 
+	if d.HasChange("enterprise_project_id") {
+		if err := migrateEnterpriseProject(d, config, epsResourceTypeECS); err != nil {
+			return err
+		}
+	}
+
+	if d.HasChange("tags") {
+		computeV1Client, err := config.computeV1Client(GetRegion(d, config))
+		if err != nil {
+			return fmt.Errorf("Error creating HuaweiCloud compute v1 client: %s", err)
+		}
+		if err := updateResourceTags(computeV1Client, d, config, computeV1Client.ServiceURL("cloudservers", d.Id(), "tags")); err != nil {
+			return err
+		}
+	}
+
+	if d.HasChange("power_state") {
+		if err := setInstancePowerState(config, computeClient, d.Id(), d.Get("power_state").(string), d.Timeout(schema.TimeoutUpdate)); err != nil {
+			return err
+		}
+	}
+
 	return resourceComputeInstanceV2Read(d, meta)
 }
 
+// resourceComputeInstanceV2Rebuild rebuilds a server with its rebuild_image_id,
+// or with its image_id or image_name once rebuild_image_id is removed, which
+// keeps the ID, the ports and the volume attachments of the server. A stopped
+// server stays stopped.
+func resourceComputeInstanceV2Rebuild(d *schema.ResourceData, config *Config, computeClient *gophercloud.ServiceClient) error {
+	imageId := d.Get("rebuild_image_id").(string)
+	if imageId == "" {
+		var err error
+		imageId, err = getImageIDFromConfig(computeClient, d)
+		if err != nil {
+			return err
+		}
+	}
+
+	rebuildOpts := &servers.RebuildOpts{
+		ImageID:     imageId,
+		Name:        d.Get("name").(string),
+		Metadata:    resourceInstanceMetadataV2(d),
+		Personality: resourceInstancePersonalityV2(d),
+	}
+	log.Printf("[DEBUG] Rebuild configuration: %#v", rebuildOpts)
+
+	// Add the password only after logging the options.
+	rebuildOpts.AdminPass = d.Get("admin_pass").(string)
+	if _, err := servers.Rebuild(computeClient, d.Id(), rebuildOpts).Extract(); err != nil {
+		return fmt.Errorf("Error rebuilding HuaweiCloud server %s: %s", d.Id(), err)
+	}
+
+	log.Printf("[DEBUG] Waiting for instance (%s) to finish rebuilding", d.Id())
+
+	stateConf := &resource.StateChangeConf{
+		Pending:    []string{"REBUILD"},
+		Target:     []string{"ACTIVE", "SHUTOFF"},
+		Refresh:    ServerV2StateRefreshFunc(computeClient, d.Id()),
+		Timeout:    d.Timeout(schema.TimeoutUpdate),
+		Delay:      config.stateRefreshDelay(10 * time.Second),
+		MinTimeout: 3 * time.Second,
+	}
+
+	if _, err := stateConf.WaitForState(); err != nil {
+		return fmt.Errorf("Error waiting for instance (%s) to rebuild: %s", d.Id(), err)
+	}
+	return nil
+}
+
 func resourceComputeInstanceV2Delete(d *schema.ResourceData, meta interface{}) error {
-	config := meta.(*Config)
+	if err := checkDeletionProtection(d, "instance"); err != nil {
+		return err
+	}
+
+	config := GetProjectConfig(d, meta.(*Config))
 	computeClient, err := config.computeV2Client(GetRegion(d, config))
 	if err != nil {
 		return fmt.Errorf("Error creating HuaweiCloud compute client: %s", err)
 	}
 
+	// A locked server can't be stopped nor deleted.
+	if d.Get("power_state").(string) == "locked" {
+		if err := lockunlock.Unlock(computeClient, d.Id()).ExtractErr(); err != nil {
+			return fmt.Errorf("Error unlocking HuaweiCloud server %s: %s", d.Id(), err)
+		}
+	}
+
 	if d.Get("stop_before_destroy").(bool) {
 		err = startstop.Stop(computeClient, d.Id()).ExtractErr()
 		if err != nil {
@@ -751,7 +924,7 @@ func resourceComputeInstanceV2Delete(d *schema.ResourceData, meta interface{}) e
 				Target:     []string{"SHUTOFF"},
 				Refresh:    ServerV2StateRefreshFunc(computeClient, d.Id()),
 				Timeout:    3 * time.Minute,
-				Delay:      10 * time.Second,
+				Delay:      config.stateRefreshDelay(10 * time.Second),
 				MinTimeout: 3 * time.Second,
 			}
 			log.Printf("[DEBUG] Waiting for instance (%s) to stop", d.Id())
@@ -772,11 +945,11 @@ func resourceComputeInstanceV2Delete(d *schema.ResourceData, meta interface{}) e
 	log.Printf("[DEBUG] Waiting for instance (%s) to delete", d.Id())
 
 	stateConf := &resource.StateChangeConf{
-		Pending:    []string{"ACTIVE", "SHUTOFF"},
+		Pending:    []string{"ACTIVE", "SHUTOFF", "PAUSED", "SUSPENDED"},
 		Target:     []string{"DELETED", "SOFT_DELETED"},
 		Refresh:    ServerV2StateRefreshFunc(computeClient, d.Id()),
 		Timeout:    d.Timeout(schema.TimeoutDelete),
-		Delay:      10 * time.Second,
+		Delay:      config.stateRefreshDelay(10 * time.Second),
 		MinTimeout: 3 * time.Second,
 	}
 
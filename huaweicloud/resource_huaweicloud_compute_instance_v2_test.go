@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/hashicorp/terraform/config"
 	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/terraform"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/fakecloud"
 
 	"github.com/gophercloud/gophercloud"
 	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/secgroups"
@@ -718,3 +720,437 @@ resource "huaweicloud_compute_instance_v2" "instance_1" {
   }
 }
 `, OS_AVAILABILITY_ZONE, OS_NETWORK_ID)
+
+func TestFakeCloudComputeInstanceV2Lifecycle(t *testing.T) {
+	t.Parallel()
+
+	srv, config := testFakeCloud(t)
+	defer srv.Close()
+
+	r := resourceComputeInstanceV2()
+	raw := map[string]interface{}{
+		"name":      "instance_1",
+		"image_id":  testFakeCloudImageID,
+		"flavor_id": "s3.small.1",
+		"metadata": map[string]interface{}{
+			"foo": "bar",
+		},
+	}
+
+	state := testFakeCloudApply(t, r, nil, raw, config)
+	server, ok := srv.Get("compute/servers", state.ID)
+	if !ok {
+		t.Fatalf("Instance %s not found in the fake cloud", state.ID)
+	}
+	if server["name"] != "instance_1" {
+		t.Fatalf("Unexpected instance name: %v", server["name"])
+	}
+	if state.Attributes["access_ip_v4"] == "" {
+		t.Fatal("Expected the fixed address of the instance to be read")
+	}
+	if state.Attributes["flavor_name"] != "s3.small.1" {
+		t.Fatalf("Unexpected flavor_name: %s", state.Attributes["flavor_name"])
+	}
+
+	// A change made outside of Terraform is read back.
+	srv.Update("compute/servers", state.ID, map[string]interface{}{
+		"metadata": map[string]interface{}{"foo": "changed"},
+	})
+	state, err := r.Refresh(state, config)
+	if err != nil {
+		t.Fatalf("Error reading instance: %s", err)
+	}
+	if v := state.Attributes["all_metadata.foo"]; v != "changed" {
+		t.Fatalf("Expected the metadata change to be read, got %s", v)
+	}
+
+	raw["name"] = "instance_2"
+	raw["metadata"] = map[string]interface{}{"foo": "bar", "abc": "def"}
+	state = testFakeCloudApply(t, r, state, raw, config)
+	server, _ = srv.Get("compute/servers", state.ID)
+	metadata, _ := server["metadata"].(map[string]interface{})
+	if server["name"] != "instance_2" || metadata["foo"] != "bar" || metadata["abc"] != "def" {
+		t.Fatalf("Instance not updated: %v", server)
+	}
+
+	testFakeCloudDestroy(t, r, state, config)
+	if _, ok := srv.Get("compute/servers", state.ID); ok {
+		t.Fatalf("Instance %s still exists", state.ID)
+	}
+	if ports := srv.List("network/ports"); len(ports) != 0 {
+		t.Fatalf("Expected the port of the instance to be deleted, got %v", ports)
+	}
+
+	// A resource deleted outside of Terraform is removed from the state.
+	state, err = r.Refresh(state, config)
+	if err != nil {
+		t.Fatalf("Error reading deleted instance: %s", err)
+	}
+	if state != nil && state.ID != "" {
+		t.Fatal("Expected the deleted instance to be removed from the state")
+	}
+}
+
+func TestFakeCloudDeletionProtection(t *testing.T) {
+	t.Parallel()
+
+	srv, config := testFakeCloud(t)
+	defer srv.Close()
+
+	r := resourceComputeInstanceV2()
+	raw := map[string]interface{}{
+		"name":                "instance_1",
+		"image_id":            testFakeCloudImageID,
+		"flavor_id":           "s3.small.1",
+		"deletion_protection": true,
+	}
+	state := testFakeCloudApply(t, r, nil, raw, config)
+
+	if _, err := r.Apply(state, &terraform.InstanceDiff{Destroy: true}, config); err == nil {
+		t.Fatal("Expected the protected instance not to be deleted")
+	}
+	if _, ok := srv.Get("compute/servers", state.ID); !ok {
+		t.Fatalf("Protected instance %s was deleted", state.ID)
+	}
+
+	raw["deletion_protection"] = false
+	state = testFakeCloudApply(t, r, state, raw, config)
+	testFakeCloudDestroy(t, r, state, config)
+	if _, ok := srv.Get("compute/servers", state.ID); ok {
+		t.Fatalf("Instance %s still exists", state.ID)
+	}
+}
+
+func TestFakeCloudComputeInstanceV2Import(t *testing.T) {
+	t.Parallel()
+
+	srv, providerConfig := testFakeCloud(t)
+	defer srv.Close()
+
+	networkID := srv.List("network/networks")[0]["id"].(string)
+	keypair := testFakeCloudApply(t, resourceComputeKeypairV2(), nil, map[string]interface{}{
+		"name": "kp_1",
+	}, providerConfig)
+
+	r := resourceComputeInstanceV2()
+	raw := map[string]interface{}{
+		"name":              "instance_1",
+		"image_id":          testFakeCloudImageID,
+		"flavor_id":         "s3.small.1",
+		"availability_zone": fakecloud.AvailabilityZone,
+		"key_pair":          keypair.ID,
+		"security_groups":   []interface{}{"default"},
+		"user_data":         "#cloud-config",
+		"config_drive":      true,
+		"metadata": map[string]interface{}{
+			"foo": "bar",
+		},
+		"network": []interface{}{
+			map[string]interface{}{"uuid": networkID},
+		},
+	}
+	created := testFakeCloudApply(t, r, nil, raw, providerConfig)
+
+	// ECS adds its own metadata to the servers.
+	server, _ := srv.Get("compute/servers", created.ID)
+	metadata := server["metadata"].(map[string]interface{})
+	srv.Update("compute/servers", created.ID, map[string]interface{}{
+		"metadata": map[string]interface{}{"foo": metadata["foo"], "charging_mode": "0", "metering.image_id": testFakeCloudImageID},
+	})
+
+	d := r.Data(nil)
+	d.SetId(created.ID)
+	results, err := r.Importer.State(d, providerConfig)
+	if err != nil {
+		t.Fatalf("Error importing instance: %s", err)
+	}
+	state, err := r.Refresh(results[0].State(), providerConfig)
+	if err != nil {
+		t.Fatalf("Error reading instance: %s", err)
+	}
+	if v := state.Attributes["network.0.uuid"]; v != networkID {
+		t.Fatalf("Expected the network ID to be imported, got %s", v)
+	}
+
+	c, err := config.NewRawConfig(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff, err := r.Diff(state, terraform.NewResourceConfig(c)); err != nil || diff != nil {
+		t.Fatalf("Expected no changes after the import, got %v, %v", diff, err)
+	}
+
+	testFakeCloudDestroy(t, r, state, providerConfig)
+}
+
+func TestFakeCloudComputeInstanceV2PowerState(t *testing.T) {
+	t.Parallel()
+
+	srv, config := testFakeCloud(t)
+	defer srv.Close()
+
+	r := resourceComputeInstanceV2()
+	raw := map[string]interface{}{
+		"name":        "instance_1",
+		"image_id":    testFakeCloudImageID,
+		"flavor_id":   "s3.small.1",
+		"power_state": "shutoff",
+	}
+
+	state := testFakeCloudApply(t, r, nil, raw, config)
+	server, _ := srv.Get("compute/servers", state.ID)
+	if server["status"] != "SHUTOFF" || state.Attributes["power_state"] != "shutoff" {
+		t.Fatalf("Expected the instance to be stopped, got %v", server["status"])
+	}
+
+	raw["power_state"] = "locked"
+	state = testFakeCloudApply(t, r, state, raw, config)
+	server, _ = srv.Get("compute/servers", state.ID)
+	if server["status"] != "ACTIVE" || server["locked"] != true {
+		t.Fatalf("Expected the instance to be started and locked, got %v, locked %v", server["status"], server["locked"])
+	}
+
+	// A power state changed outside of Terraform is read back.
+	srv.Update("compute/servers", state.ID, map[string]interface{}{"status": "PAUSED", "locked": false})
+	state, err := r.Refresh(state, config)
+	if err != nil {
+		t.Fatalf("Error reading instance: %s", err)
+	}
+	if v := state.Attributes["power_state"]; v != "paused" {
+		t.Fatalf("Expected the power state change to be read, got %s", v)
+	}
+
+	state = testFakeCloudApply(t, r, state, raw, config)
+	server, _ = srv.Get("compute/servers", state.ID)
+	if server["status"] != "ACTIVE" || server["locked"] != true {
+		t.Fatalf("Expected the instance to be unpaused and locked, got %v, locked %v", server["status"], server["locked"])
+	}
+
+	// A locked instance is unlocked before being deleted.
+	testFakeCloudDestroy(t, r, state, config)
+	if _, ok := srv.Get("compute/servers", state.ID); ok {
+		t.Fatalf("Instance %s still exists", state.ID)
+	}
+}
+
+func TestFakeCloudComputeInstanceV2Rebuild(t *testing.T) {
+	t.Parallel()
+
+	srv, providerConfig := testFakeCloud(t)
+	defer srv.Close()
+
+	imageID := srv.Add("compute/images", map[string]interface{}{
+		"name": "Ubuntu 20.04", "status": "ACTIVE",
+	})
+
+	r := resourceComputeInstanceV2()
+	raw := map[string]interface{}{
+		"name":              "instance_1",
+		"image_id":          testFakeCloudImageID,
+		"flavor_id":         "s3.small.1",
+		"availability_zone": fakecloud.AvailabilityZone,
+	}
+	state := testFakeCloudApply(t, r, nil, raw, providerConfig)
+	ports := srv.List("network/ports")
+
+	// Without rebuild_image_id, changing the image replaces the instance.
+	raw["image_id"] = imageID
+	c, err := config.NewRawConfig(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	diff, err := r.Diff(state, terraform.NewResourceConfig(c))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff == nil || !diff.RequiresNew() {
+		t.Fatalf("Expected a change of the image to replace the instance, got %v", diff)
+	}
+
+	raw["image_id"] = testFakeCloudImageID
+	raw["rebuild_image_id"] = imageID
+	rebuilt := testFakeCloudApply(t, r, state, raw, providerConfig)
+	if rebuilt.ID != state.ID {
+		t.Fatalf("Expected the instance to be rebuilt in place, got %s", rebuilt.ID)
+	}
+	server, _ := srv.Get("compute/servers", state.ID)
+	if image := server["image"].(map[string]interface{}); image["id"] != imageID {
+		t.Fatalf("Instance not rebuilt: %v", image)
+	}
+	if v := srv.List("network/ports"); len(v) != len(ports) || v[0]["id"] != ports[0]["id"] {
+		t.Fatalf("Expected the ports of the instance to be kept, got %v", v)
+	}
+
+	// The image the instance was created with is kept.
+	c, err = config.NewRawConfig(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff, err := r.Diff(rebuilt, terraform.NewResourceConfig(c)); err != nil || diff != nil {
+		t.Fatalf("Expected no changes after the rebuild, got %v, %v", diff, err)
+	}
+
+	// A stopped instance is rebuilt without being started. Removing
+	// rebuild_image_id rebuilds the instance with its image_id.
+	raw["power_state"] = "shutoff"
+	rebuilt = testFakeCloudApply(t, r, rebuilt, raw, providerConfig)
+	delete(raw, "rebuild_image_id")
+	rebuilt = testFakeCloudApply(t, r, rebuilt, raw, providerConfig)
+	if rebuilt.Attributes["power_state"] != "shutoff" {
+		t.Fatalf("Expected the instance to stay stopped, got %s", rebuilt.Attributes["power_state"])
+	}
+	server, _ = srv.Get("compute/servers", state.ID)
+	if image := server["image"].(map[string]interface{}); image["id"] != testFakeCloudImageID {
+		t.Fatalf("Instance not rebuilt with its image_id: %v", image)
+	}
+	if rebuilt.Attributes["image_name"] != "Ubuntu 18.04" {
+		t.Fatalf("Unexpected image_name: %s", rebuilt.Attributes["image_name"])
+	}
+
+	testFakeCloudDestroy(t, r, rebuilt, providerConfig)
+}
+
+func TestFakeCloudComputeInstanceV2UpdateNetworks(t *testing.T) {
+	t.Parallel()
+
+	srv, providerConfig := testFakeCloud(t)
+	defer srv.Close()
+
+	networkID := srv.List("network/networks")[0]["id"]
+	network2ID := srv.Add("network/networks", map[string]interface{}{"name": "fake-network-2"})
+	srv.Add("network/subnets", map[string]interface{}{
+		"name": "fake-subnet-2", "network_id": network2ID, "cidr": "10.0.0.0/24",
+	})
+
+	r := resourceComputeInstanceV2()
+	raw := map[string]interface{}{
+		"name":              "instance_1",
+		"image_id":          testFakeCloudImageID,
+		"flavor_id":         "s3.small.1",
+		"availability_zone": fakecloud.AvailabilityZone,
+		"network": []interface{}{
+			map[string]interface{}{"uuid": networkID},
+		},
+	}
+	state := testFakeCloudApply(t, r, nil, raw, providerConfig)
+	fixedIP := state.Attributes["network.0.fixed_ip_v4"]
+
+	// Adding a network attaches an interface without recreating the server.
+	raw["network"] = []interface{}{
+		map[string]interface{}{"uuid": networkID},
+		map[string]interface{}{"name": "fake-network-2"},
+	}
+	updated := testFakeCloudApply(t, r, state, raw, providerConfig)
+	if updated.ID != state.ID {
+		t.Fatalf("Expected the instance to be updated in place, got %s", updated.ID)
+	}
+	if v := srv.List("network/ports"); len(v) != 2 {
+		t.Fatalf("Expected 2 ports, got %v", v)
+	}
+	if updated.Attributes["network.0.fixed_ip_v4"] != fixedIP {
+		t.Fatalf("Expected the fixed IP %s to be kept, got %s", fixedIP, updated.Attributes["network.0.fixed_ip_v4"])
+	}
+	if updated.Attributes["network.1.uuid"] != network2ID {
+		t.Fatalf("Unexpected network.1.uuid: %s", updated.Attributes["network.1.uuid"])
+	}
+	fixedIP2 := updated.Attributes["network.1.fixed_ip_v4"]
+
+	// Removing the first network keeps the interface of the second one. The
+	// interfaces are found without their MAC addresses, as after an import.
+	updated.Attributes["network.0.mac"] = ""
+	updated.Attributes["network.1.mac"] = ""
+	raw["network"] = []interface{}{
+		map[string]interface{}{"name": "fake-network-2"},
+	}
+	updated = testFakeCloudApply(t, r, updated, raw, providerConfig)
+	ports := srv.List("network/ports")
+	if len(ports) != 1 || ports[0]["network_id"] != network2ID {
+		t.Fatalf("Expected only the port on the second network, got %v", ports)
+	}
+	if updated.Attributes["network.#"] != "1" || updated.Attributes["network.0.uuid"] != network2ID {
+		t.Fatalf("Unexpected networks: %v", updated.Attributes)
+	}
+	if updated.Attributes["network.0.fixed_ip_v4"] != fixedIP2 {
+		t.Fatalf("Expected the fixed IP %s to be kept, got %s", fixedIP2, updated.Attributes["network.0.fixed_ip_v4"])
+	}
+
+	c, err := config.NewRawConfig(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	diff, err := r.Diff(updated, terraform.NewResourceConfig(c))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !diff.Empty() {
+		t.Fatalf("Expected no diff after updating the networks, got %#v", diff)
+	}
+
+	// An interface which can't be found by its network and its fixed IP
+	// isn't silently assumed to be detached.
+	state = updated.DeepCopy()
+	state.Attributes["network.0.mac"] = ""
+	state.Attributes["network.0.fixed_ip_v4"] = "10.0.0.250"
+	raw["network"] = []interface{}{
+		map[string]interface{}{"uuid": networkID},
+	}
+	c, err = config.NewRawConfig(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	diff, err = r.Diff(state, terraform.NewResourceConfig(c))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Apply(state, diff, providerConfig); err == nil {
+		t.Fatal("Expected an error when the interface to detach can't be found")
+	}
+
+	testFakeCloudDestroy(t, r, updated, providerConfig)
+}
+
+func TestFakeCloudComputeInstanceV2EnterpriseProjectID(t *testing.T) {
+	t.Parallel()
+
+	srv, providerConfig := testFakeCloud(t)
+	defer srv.Close()
+
+	r := resourceComputeInstanceV2()
+	raw := map[string]interface{}{
+		"name":                  "instance_1",
+		"image_id":              testFakeCloudImageID,
+		"flavor_id":             "s3.small.1",
+		"availability_zone":     fakecloud.AvailabilityZone,
+		"enterprise_project_id": "ep-1",
+	}
+	state := testFakeCloudApply(t, r, nil, raw, providerConfig)
+	if server, _ := srv.Get("compute/servers", state.ID); server["enterprise_project_id"] != "ep-1" {
+		t.Fatalf("Expected the instance in enterprise project ep-1, got %v", server["enterprise_project_id"])
+	}
+
+	// The enterprise project isn't returned by Nova, it is read from ECS.
+	srv.Update("compute/servers", state.ID, map[string]interface{}{"enterprise_project_id": "ep-2"})
+	state, err := r.Refresh(state, providerConfig)
+	if err != nil {
+		t.Fatalf("Error reading instance: %s", err)
+	}
+	if v := state.Attributes["enterprise_project_id"]; v != "ep-2" {
+		t.Fatalf("Expected the instance migrated to ep-2 to be detected, got %s", v)
+	}
+
+	// Moving the instance back to the configured enterprise project is an update.
+	state = testFakeCloudApply(t, r, state, raw, providerConfig)
+	if server, _ := srv.Get("compute/servers", state.ID); server["enterprise_project_id"] != "ep-1" {
+		t.Fatalf("Expected the instance back in enterprise project ep-1, got %v", server["enterprise_project_id"])
+	}
+
+	c, err := config.NewRawConfig(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff, err := r.Diff(state, terraform.NewResourceConfig(c)); err != nil || diff != nil {
+		t.Fatalf("Expected no changes after the update, got %v, %v", diff, err)
+	}
+
+	testFakeCloudDestroy(t, r, state, providerConfig)
+}
@@ -0,0 +1,237 @@
+package huaweicloud
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/attachinterfaces"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func resourceComputeInterfaceAttachV2() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceComputeInterfaceAttachV2Create,
+		Read:   resourceComputeInterfaceAttachV2Read,
+		Delete: resourceComputeInterfaceAttachV2Delete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"region": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+			"project_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+
+			"instance_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"port_id": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"network_id"},
+			},
+
+			"network_id": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"port_id"},
+			},
+
+			"fixed_ip": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"port_id"},
+			},
+
+			"mac": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceComputeInterfaceAttachV2Create(d *schema.ResourceData, meta interface{}) error {
+	config := GetProjectConfig(d, meta.(*Config))
+	computeClient, err := config.computeV2Client(GetRegion(d, config))
+	if err != nil {
+		return fmt.Errorf("Error creating HuaweiCloud compute client: %s", err)
+	}
+
+	instanceId := d.Get("instance_id").(string)
+	portId := d.Get("port_id").(string)
+	networkId := d.Get("network_id").(string)
+	if portId == "" && networkId == "" {
+		return fmt.Errorf("One of port_id or network_id must be set")
+	}
+
+	attachOpts := attachinterfaces.CreateOpts{
+		PortID:    portId,
+		NetworkID: networkId,
+	}
+	if v, ok := d.GetOk("fixed_ip"); ok {
+		attachOpts.FixedIPs = []attachinterfaces.FixedIP{{IPAddress: v.(string)}}
+	}
+
+	log.Printf("[DEBUG] Creating interface attachment: %#v", attachOpts)
+
+	attachment, err := attachinterfaces.Create(computeClient, instanceId, attachOpts).Extract()
+	if err != nil {
+		return fmt.Errorf("Error attaching HuaweiCloud interface: %s", err)
+	}
+
+	if err := waitForInterfaceAttached(config, computeClient, instanceId, attachment.PortID, d.Timeout(schema.TimeoutCreate)); err != nil {
+		return err
+	}
+
+	log.Printf("[DEBUG] Created interface attachment: %#v", attachment)
+
+	// An attachment is identified by the instance and its port.
+	d.SetId(fmt.Sprintf("%s/%s", instanceId, attachment.PortID))
+
+	return resourceComputeInterfaceAttachV2Read(d, meta)
+}
+
+func resourceComputeInterfaceAttachV2Read(d *schema.ResourceData, meta interface{}) error {
+	config := GetProjectConfig(d, meta.(*Config))
+	computeClient, err := config.computeV2Client(GetRegion(d, config))
+	if err != nil {
+		return fmt.Errorf("Error creating HuaweiCloud compute client: %s", err)
+	}
+
+	instanceId, portId, err := parseComputeInterfaceAttachmentId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	attachment, err := attachinterfaces.Get(computeClient, instanceId, portId).Extract()
+	if err != nil {
+		return CheckDeleted(d, err, "compute_interface_attach")
+	}
+
+	log.Printf("[DEBUG] Retrieved interface attachment: %#v", attachment)
+
+	d.Set("instance_id", instanceId)
+	d.Set("port_id", attachment.PortID)
+	d.Set("network_id", attachment.NetID)
+	d.Set("mac", attachment.MACAddr)
+	if len(attachment.FixedIPs) > 0 {
+		d.Set("fixed_ip", attachment.FixedIPs[0].IPAddress)
+	}
+	d.Set("region", GetRegion(d, config))
+
+	return nil
+}
+
+func resourceComputeInterfaceAttachV2Delete(d *schema.ResourceData, meta interface{}) error {
+	config := GetProjectConfig(d, meta.(*Config))
+	computeClient, err := config.computeV2Client(GetRegion(d, config))
+	if err != nil {
+		return fmt.Errorf("Error creating HuaweiCloud compute client: %s", err)
+	}
+
+	instanceId, portId, err := parseComputeInterfaceAttachmentId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	err = attachinterfaces.Delete(computeClient, instanceId, portId).ExtractErr()
+	if err != nil {
+		if _, ok := err.(gophercloud.ErrDefault404); ok {
+			return nil
+		}
+		return fmt.Errorf("Error detaching HuaweiCloud interface: %s", err)
+	}
+
+	return waitForInterfaceDetached(config, computeClient, instanceId, portId, d.Timeout(schema.TimeoutDelete))
+}
+
+func waitForInterfaceAttached(
+	config *Config, computeClient *gophercloud.ServiceClient, instanceId, portId string, timeout time.Duration) error {
+	stateConf := &resource.StateChangeConf{
+		Pending:    []string{"ATTACHING"},
+		Target:     []string{"ATTACHED"},
+		Refresh:    resourceComputeInterfaceAttachV2StateFunc(computeClient, instanceId, portId),
+		Timeout:    timeout,
+		Delay:      config.stateRefreshDelay(5 * time.Second),
+		MinTimeout: 3 * time.Second,
+	}
+
+	if _, err := stateConf.WaitForState(); err != nil {
+		return fmt.Errorf("Error attaching HuaweiCloud port %s to instance %s: %s", portId, instanceId, err)
+	}
+	return nil
+}
+
+func waitForInterfaceDetached(
+	config *Config, computeClient *gophercloud.ServiceClient, instanceId, portId string, timeout time.Duration) error {
+	stateConf := &resource.StateChangeConf{
+		Pending:    []string{"ATTACHING", "ATTACHED"},
+		Target:     []string{"DETACHED"},
+		Refresh:    resourceComputeInterfaceAttachV2StateFunc(computeClient, instanceId, portId),
+		Timeout:    timeout,
+		Delay:      config.stateRefreshDelay(5 * time.Second),
+		MinTimeout: 3 * time.Second,
+	}
+
+	if _, err := stateConf.WaitForState(); err != nil {
+		return fmt.Errorf("Error detaching HuaweiCloud port %s from instance %s: %s", portId, instanceId, err)
+	}
+	return nil
+}
+
+// resourceComputeInterfaceAttachV2StateFunc returns whether a port is attached
+// to an instance. A port is attached once it is active.
+func resourceComputeInterfaceAttachV2StateFunc(
+	computeClient *gophercloud.ServiceClient, instanceId, portId string) resource.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		attachment, err := attachinterfaces.Get(computeClient, instanceId, portId).Extract()
+		if err != nil {
+			if _, ok := err.(gophercloud.ErrDefault404); ok {
+				return attachment, "DETACHED", nil
+			}
+			return nil, "", err
+		}
+
+		if attachment.PortState == "ACTIVE" {
+			return attachment, "ATTACHED", nil
+		}
+		return attachment, "ATTACHING", nil
+	}
+}
+
+func parseComputeInterfaceAttachmentId(id string) (string, string, error) {
+	idParts := strings.Split(id, "/")
+	if len(idParts) < 2 {
+		return "", "", fmt.Errorf("Unable to determine interface attachment ID")
+	}
+
+	return idParts[0], idParts[1], nil
+}
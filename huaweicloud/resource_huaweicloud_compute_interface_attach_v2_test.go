@@ -0,0 +1,294 @@
+package huaweicloud
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/fakecloud"
+
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/attachinterfaces"
+)
+
+func TestAccComputeV2InterfaceAttach_basic(t *testing.T) {
+	var ai attachinterfaces.Interface
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckComputeV2InterfaceAttachDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccComputeV2InterfaceAttach_basic,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckComputeV2InterfaceAttachExists("huaweicloud_compute_interface_attach_v2.ai_1", &ai),
+					resource.TestCheckResourceAttr(
+						"huaweicloud_compute_interface_attach_v2.ai_1", "fixed_ip", "192.168.199.24"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckComputeV2InterfaceAttachDestroy(s *terraform.State) error {
+	config := testAccProvider.Meta().(*Config)
+	computeClient, err := config.computeV2Client(OS_REGION_NAME)
+	if err != nil {
+		return fmt.Errorf("Error creating HuaweiCloud compute client: %s", err)
+	}
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "huaweicloud_compute_interface_attach_v2" {
+			continue
+		}
+
+		instanceId, portId, err := parseComputeInterfaceAttachmentId(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		_, err = attachinterfaces.Get(computeClient, instanceId, portId).Extract()
+		if err == nil {
+			return fmt.Errorf("Interface attachment still exists")
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckComputeV2InterfaceAttachExists(n string, ai *attachinterfaces.Interface) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No ID is set")
+		}
+
+		config := testAccProvider.Meta().(*Config)
+		computeClient, err := config.computeV2Client(OS_REGION_NAME)
+		if err != nil {
+			return fmt.Errorf("Error creating HuaweiCloud compute client: %s", err)
+		}
+
+		instanceId, portId, err := parseComputeInterfaceAttachmentId(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		found, err := attachinterfaces.Get(computeClient, instanceId, portId).Extract()
+		if err != nil {
+			return err
+		}
+
+		if found.PortID != portId {
+			return fmt.Errorf("InterfaceAttach not found")
+		}
+
+		*ai = *found
+
+		return nil
+	}
+}
+
+var testAccComputeV2InterfaceAttach_basic = fmt.Sprintf(`
+resource "huaweicloud_networking_network_v2" "network_1" {
+  name = "network_1"
+  admin_state_up = "true"
+}
+
+resource "huaweicloud_networking_subnet_v2" "subnet_1" {
+  name = "subnet_1"
+  cidr = "192.168.199.0/24"
+  ip_version = 4
+  network_id = "${huaweicloud_networking_network_v2.network_1.id}"
+}
+
+resource "huaweicloud_compute_instance_v2" "instance_1" {
+  name = "instance_1"
+  security_groups = ["default"]
+  availability_zone = "%s"
+  network {
+    uuid = "%s"
+  }
+}
+
+resource "huaweicloud_compute_interface_attach_v2" "ai_1" {
+  instance_id = "${huaweicloud_compute_instance_v2.instance_1.id}"
+  network_id = "${huaweicloud_networking_subnet_v2.subnet_1.network_id}"
+  fixed_ip = "192.168.199.24"
+}
+`, OS_AVAILABILITY_ZONE, OS_NETWORK_ID)
+
+func TestFakeCloudComputeInterfaceAttachV2(t *testing.T) {
+	t.Parallel()
+
+	srv, providerConfig := testFakeCloud(t)
+	defer srv.Close()
+
+	network2ID := srv.Add("network/networks", map[string]interface{}{"name": "fake-network-2"})
+	srv.Add("network/subnets", map[string]interface{}{
+		"name": "fake-subnet-2", "network_id": network2ID, "cidr": "10.0.0.0/24",
+	})
+
+	instance := resourceComputeInstanceV2()
+	instanceRaw := map[string]interface{}{
+		"name":              "instance_1",
+		"image_id":          testFakeCloudImageID,
+		"flavor_id":         "s3.small.1",
+		"availability_zone": fakecloud.AvailabilityZone,
+	}
+	instanceState := testFakeCloudApply(t, instance, nil, instanceRaw, providerConfig)
+
+	r := resourceComputeInterfaceAttachV2()
+	state := testFakeCloudApply(t, r, nil, map[string]interface{}{
+		"instance_id": instanceState.ID,
+		"network_id":  network2ID,
+		"fixed_ip":    "10.0.0.50",
+	}, providerConfig)
+
+	portID := state.Attributes["port_id"]
+	if state.ID != instanceState.ID+"/"+portID {
+		t.Fatalf("Unexpected ID: %s", state.ID)
+	}
+	port, ok := srv.Get("network/ports", portID)
+	if !ok || port["device_id"] != instanceState.ID {
+		t.Fatalf("Port %s not attached to the instance: %v", portID, port)
+	}
+	if state.Attributes["fixed_ip"] != "10.0.0.50" || state.Attributes["mac"] != port["mac_address"] {
+		t.Fatalf("Unexpected attributes: %v", state.Attributes)
+	}
+	server, _ := srv.Get("compute/servers", instanceState.ID)
+	if _, ok := server["addresses"].(map[string]interface{})["fake-network-2"]; !ok {
+		t.Fatalf("Interface not in the addresses of the instance: %v", server["addresses"])
+	}
+
+	// The interface doesn't change the networks of the instance.
+	c, err := config.NewRawConfig(instanceRaw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	instanceState, err = instance.Refresh(instanceState, providerConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	diff, err := instance.Diff(instanceState, terraform.NewResourceConfig(c))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !diff.Empty() {
+		t.Fatalf("Expected no instance diff after attaching an interface, got %#v", diff)
+	}
+	if v := instanceState.Attributes["network.#"]; v != "1" {
+		t.Fatalf("Expected the interface not to be a network of the instance, got %s networks", v)
+	}
+
+	testFakeCloudDestroy(t, r, state, providerConfig)
+	if _, ok := srv.Get("network/ports", portID); ok {
+		t.Fatalf("Port %s not deleted with the attachment", portID)
+	}
+
+	testFakeCloudDestroy(t, instance, instanceState, providerConfig)
+}
+
+func TestFakeCloudComputeInterfaceAttachV2InstanceNetworks(t *testing.T) {
+	t.Parallel()
+
+	srv, providerConfig := testFakeCloud(t)
+	defer srv.Close()
+
+	networkID := srv.List("network/networks")[0]["id"]
+	network2ID := srv.Add("network/networks", map[string]interface{}{"name": "fake-network-2"})
+	srv.Add("network/subnets", map[string]interface{}{
+		"name": "fake-subnet-2", "network_id": network2ID, "cidr": "10.0.0.0/24",
+	})
+
+	instance := resourceComputeInstanceV2()
+	instanceRaw := map[string]interface{}{
+		"name":              "instance_1",
+		"image_id":          testFakeCloudImageID,
+		"flavor_id":         "s3.small.1",
+		"availability_zone": fakecloud.AvailabilityZone,
+		"network": []interface{}{
+			map[string]interface{}{"uuid": networkID},
+		},
+	}
+	instanceState := testFakeCloudApply(t, instance, nil, instanceRaw, providerConfig)
+	mac := instanceState.Attributes["network.0.mac"]
+
+	// Interfaces on the network of the instance and on another network.
+	r := resourceComputeInterfaceAttachV2()
+	var attachments []*terraform.InstanceState
+	for _, id := range []interface{}{networkID, network2ID} {
+		attachments = append(attachments, testFakeCloudApply(t, r, nil, map[string]interface{}{
+			"instance_id": instanceState.ID,
+			"network_id":  id,
+		}, providerConfig))
+	}
+	attached := func() {
+		for _, state := range attachments {
+			portID := state.Attributes["port_id"]
+			if port, ok := srv.Get("network/ports", portID); !ok || port["device_id"] != instanceState.ID {
+				t.Fatalf("Port %s of the attachment was detached: %v", portID, port)
+			}
+		}
+	}
+
+	// The interfaces aren't networks of the instance.
+	instanceState, err := instance.Refresh(instanceState, providerConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if instanceState.Attributes["network.#"] != "1" || instanceState.Attributes["network.0.mac"] != mac {
+		t.Fatalf("Unexpected networks: %v", instanceState.Attributes)
+	}
+
+	// Adding and removing a network of the instance leaves the interfaces.
+	instanceRaw["network"] = []interface{}{
+		map[string]interface{}{"uuid": networkID},
+		map[string]interface{}{"uuid": network2ID},
+	}
+	instanceState = testFakeCloudApply(t, instance, instanceState, instanceRaw, providerConfig)
+	attached()
+	if v := srv.List("network/ports"); len(v) != 4 {
+		t.Fatalf("Expected 4 ports, got %v", v)
+	}
+	if instanceState.Attributes["network.#"] != "2" || instanceState.Attributes["network.0.mac"] != mac {
+		t.Fatalf("Unexpected networks: %v", instanceState.Attributes)
+	}
+	for _, state := range attachments {
+		if instanceState.Attributes["network.1.mac"] == state.Attributes["mac"] {
+			t.Fatalf("Expected a new interface for the network, got the interface of %s", state.ID)
+		}
+	}
+
+	instanceRaw["network"] = []interface{}{
+		map[string]interface{}{"uuid": networkID},
+	}
+	instanceState = testFakeCloudApply(t, instance, instanceState, instanceRaw, providerConfig)
+	attached()
+	if v := srv.List("network/ports"); len(v) != 3 {
+		t.Fatalf("Expected 3 ports, got %v", v)
+	}
+
+	c, err := config.NewRawConfig(instanceRaw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	instanceState, err = instance.Refresh(instanceState, providerConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff, err := instance.Diff(instanceState, terraform.NewResourceConfig(c)); err != nil || !diff.Empty() {
+		t.Fatalf("Expected no instance diff, got %#v, %v", diff, err)
+	}
+
+	for _, state := range attachments {
+		testFakeCloudDestroy(t, r, state, providerConfig)
+	}
+	testFakeCloudDestroy(t, instance, instanceState, providerConfig)
+}
@@ -24,6 +24,11 @@ func resourceComputeKeypairV2() *schema.Resource {
 				Computed: true,
 				ForceNew: true,
 			},
+			"project_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
 
 			"name": &schema.Schema{
 				Type:     schema.TypeString,
@@ -45,7 +50,7 @@ func resourceComputeKeypairV2() *schema.Resource {
 }
 
 func resourceComputeKeypairV2Create(d *schema.ResourceData, meta interface{}) error {
-	config := meta.(*Config)
+	config := GetProjectConfig(d, meta.(*Config))
 	computeClient, err := config.computeV2Client(GetRegion(d, config))
 	if err != nil {
 		return fmt.Errorf("Error creating HuaweiCloud compute client: %s", err)
@@ -71,7 +76,7 @@ func resourceComputeKeypairV2Create(d *schema.ResourceData, meta interface{}) er
 }
 
 func resourceComputeKeypairV2Read(d *schema.ResourceData, meta interface{}) error {
-	config := meta.(*Config)
+	config := GetProjectConfig(d, meta.(*Config))
 	computeClient, err := config.computeV2Client(GetRegion(d, config))
 	if err != nil {
 		return fmt.Errorf("Error creating HuaweiCloud compute client: %s", err)
@@ -90,7 +95,7 @@ func resourceComputeKeypairV2Read(d *schema.ResourceData, meta interface{}) erro
 }
 
 func resourceComputeKeypairV2Delete(d *schema.ResourceData, meta interface{}) error {
-	config := meta.(*Config)
+	config := GetProjectConfig(d, meta.(*Config))
 	computeClient, err := config.computeV2Client(GetRegion(d, config))
 	if err != nil {
 		return fmt.Errorf("Error creating HuaweiCloud compute client: %s", err)
@@ -35,6 +35,11 @@ func resourceComputeSecGroupV2() *schema.Resource {
 				Computed: true,
 				ForceNew: true,
 			},
+			"project_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
 
 			"name": &schema.Schema{
 				Type:     schema.TypeString,
@@ -99,7 +104,7 @@ func resourceComputeSecGroupV2() *schema.Resource {
 }
 
 func resourceComputeSecGroupV2Create(d *schema.ResourceData, meta interface{}) error {
-	config := meta.(*Config)
+	config := GetProjectConfig(d, meta.(*Config))
 	computeClient, err := config.computeV2Client(GetRegion(d, config))
 	if err != nil {
 		return fmt.Errorf("Error creating HuaweiCloud compute client: %s", err)
@@ -137,7 +142,7 @@ func resourceComputeSecGroupV2Create(d *schema.ResourceData, meta interface{}) e
 }
 
 func resourceComputeSecGroupV2Read(d *schema.ResourceData, meta interface{}) error {
-	config := meta.(*Config)
+	config := GetProjectConfig(d, meta.(*Config))
 	computeClient, err := config.computeV2Client(GetRegion(d, config))
 	if err != nil {
 		return fmt.Errorf("Error creating HuaweiCloud compute client: %s", err)
@@ -164,7 +169,7 @@ func resourceComputeSecGroupV2Read(d *schema.ResourceData, meta interface{}) err
 }
 
 func resourceComputeSecGroupV2Update(d *schema.ResourceData, meta interface{}) error {
-	config := meta.(*Config)
+	config := GetProjectConfig(d, meta.(*Config))
 	computeClient, err := config.computeV2Client(GetRegion(d, config))
 	if err != nil {
 		return fmt.Errorf("Error creating HuaweiCloud compute client: %s", err)
@@ -219,7 +224,7 @@ func resourceComputeSecGroupV2Update(d *schema.ResourceData, meta interface{}) e
 }
 
 func resourceComputeSecGroupV2Delete(d *schema.ResourceData, meta interface{}) error {
-	config := meta.(*Config)
+	config := GetProjectConfig(d, meta.(*Config))
 	computeClient, err := config.computeV2Client(GetRegion(d, config))
 	if err != nil {
 		return fmt.Errorf("Error creating HuaweiCloud compute client: %s", err)
@@ -34,6 +34,11 @@ func resourceComputeVolumeAttachV2() *schema.Resource {
 				Computed: true,
 				ForceNew: true,
 			},
+			"project_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
 
 			"instance_id": &schema.Schema{
 				Type:     schema.TypeString,
@@ -58,7 +63,7 @@ func resourceComputeVolumeAttachV2() *schema.Resource {
 }
 
 func resourceComputeVolumeAttachV2Create(d *schema.ResourceData, meta interface{}) error {
-	config := meta.(*Config)
+	config := GetProjectConfig(d, meta.(*Config))
 	computeClient, err := config.computeV2Client(GetRegion(d, config))
 	if err != nil {
 		return fmt.Errorf("Error creating HuaweiCloud compute client: %s", err)
@@ -109,7 +114,7 @@ func resourceComputeVolumeAttachV2Create(d *schema.ResourceData, meta interface{
 }
 
 func resourceComputeVolumeAttachV2Read(d *schema.ResourceData, meta interface{}) error {
-	config := meta.(*Config)
+	config := GetProjectConfig(d, meta.(*Config))
 	computeClient, err := config.computeV2Client(GetRegion(d, config))
 	if err != nil {
 		return fmt.Errorf("Error creating HuaweiCloud compute client: %s", err)
@@ -137,7 +142,7 @@ func resourceComputeVolumeAttachV2Read(d *schema.ResourceData, meta interface{})
 }
 
 func resourceComputeVolumeAttachV2Delete(d *schema.ResourceData, meta interface{}) error {
-	config := meta.(*Config)
+	config := GetProjectConfig(d, meta.(*Config))
 	computeClient, err := config.computeV2Client(GetRegion(d, config))
 	if err != nil {
 		return fmt.Errorf("Error creating HuaweiCloud compute client: %s", err)
@@ -16,7 +16,7 @@ func resourceDNSZoneV2() *schema.Resource {
 	return &schema.Resource{
 		Create: resourceDNSZoneV2Create,
 		Read:   resourceDNSZoneV2Read,
-		//Update: resourceDNSZoneV2Update,
+		Update: resourceDNSZoneV2Update,
 		Delete: resourceDNSZoneV2Delete,
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
@@ -35,6 +35,9 @@ func resourceDNSZoneV2() *schema.Resource {
 				Computed: true,
 				ForceNew: true,
 			},
+			"enterprise_project_id": enterpriseProjectIDSchema(),
+			"tags":                  tagsSchema(),
+			"tags_all":              tagsAllSchema(),
 			"name": &schema.Schema{
 				Type:     schema.TypeString,
 				Required: true,
@@ -161,6 +164,9 @@ func resourceDNSZoneV2Create(d *schema.ResourceData, meta interface{}) error {
 	// Add zone_type to the list.  We do this to keep GopherCloud HuaweiCloud standard.
 	vs["zone_type"] = zone_type
 	vs["router"] = resourceDNSRouter(d)
+	if v, ok := d.GetOk("enterprise_project_id"); ok {
+		vs["enterprise_project_id"] = v.(string)
+	}
 	createOpts := ZoneCreateOpts{
 		zones.CreateOpts{
 			Name: d.Get("name").(string),
@@ -199,6 +205,10 @@ func resourceDNSZoneV2Create(d *schema.ResourceData, meta interface{}) error {
 
 	d.SetId(n.ID)
 
+	if err := updateResourceTags(dnsClient, d, config, resourceDNSZoneV2TagsURL(dnsClient, d)); err != nil {
+		return err
+	}
+
 	log.Printf("[DEBUG] Created HuaweiCloud DNS Zone %s: %#v", n.ID, n)
 	return resourceDNSZoneV2Read(d, meta)
 }
@@ -210,7 +220,8 @@ func resourceDNSZoneV2Read(d *schema.ResourceData, meta interface{}) error {
 		return fmt.Errorf("Error creating HuaweiCloud DNS client: %s", err)
 	}
 
-	n, err := zones.Get(dnsClient, d.Id()).Extract()
+	result := zones.Get(dnsClient, d.Id())
+	n, err := result.Extract()
 	if err != nil {
 		return CheckDeleted(d, err, "zone")
 	}
@@ -230,6 +241,11 @@ func resourceDNSZoneV2Read(d *schema.ResourceData, meta interface{}) error {
 	}
 	d.Set("region", GetRegion(d, config))
 	d.Set("zone_type", n.ZoneType)
+	setEnterpriseProjectID(d, result.Body, "")
+
+	if err := readResourceTags(dnsClient, d, config, resourceDNSZoneV2TagsURL(dnsClient, d)); err != nil {
+		return err
+	}
 	//log.Printf("[DEBUG] resourceDNSZoneV2Read: %+v", n)
 
 	return nil
@@ -261,24 +277,42 @@ func resourceDNSZoneV2Update(d *schema.ResourceData, meta interface{}) error {
 		updateOpts.Description = d.Get("description").(string)
 	}
 
-	log.Printf("[DEBUG] Updating Zone %s with options: %#v", d.Id(), updateOpts)
+	if d.HasChange("email") || d.HasChange("ttl") || d.HasChange("description") {
+		log.Printf("[DEBUG] Updating Zone %s with options: %#v", d.Id(), updateOpts)
 
-	_, err = zones.Update(dnsClient, d.Id(), updateOpts).Extract()
-	if err != nil {
-		return fmt.Errorf("Error updating HuaweiCloud DNS Zone: %s", err)
+		_, err = zones.Update(dnsClient, d.Id(), updateOpts).Extract()
+		if err != nil {
+			return fmt.Errorf("Error updating HuaweiCloud DNS Zone: %s", err)
+		}
+
+		log.Printf("[DEBUG] Waiting for DNS Zone (%s) to update", d.Id())
+		stateConf := &resource.StateChangeConf{
+			Target:     []string{"ACTIVE"},
+			Pending:    []string{"PENDING"},
+			Refresh:    waitForDNSZone(dnsClient, d.Id()),
+			Timeout:    d.Timeout(schema.TimeoutUpdate),
+			Delay:      5 * time.Second,
+			MinTimeout: 3 * time.Second,
+		}
+
+		_, err = stateConf.WaitForState()
 	}
 
-	log.Printf("[DEBUG] Waiting for DNS Zone (%s) to update", d.Id())
-	stateConf := &resource.StateChangeConf{
-		Target:     []string{"ACTIVE"},
-		Pending:    []string{"PENDING"},
-		Refresh:    waitForDNSZone(dnsClient, d.Id()),
-		Timeout:    d.Timeout(schema.TimeoutUpdate),
-		Delay:      5 * time.Second,
-		MinTimeout: 3 * time.Second,
+	if d.HasChange("enterprise_project_id") {
+		resourceType := epsResourceTypeDNSPublicZone
+		if d.Get("zone_type").(string) == "private" {
+			resourceType = epsResourceTypeDNSPrivateZone
+		}
+		if err := migrateEnterpriseProject(d, config, resourceType); err != nil {
+			return err
+		}
 	}
 
-	_, err = stateConf.WaitForState()
+	if d.HasChange("tags") {
+		if err := updateResourceTags(dnsClient, d, config, resourceDNSZoneV2TagsURL(dnsClient, d)); err != nil {
+			return err
+		}
+	}
 
 	return resourceDNSZoneV2Read(d, meta)
 }
@@ -335,6 +369,16 @@ func resourceDNSZoneV2ValidType(v interface{}, k string) (ws []string, errors []
 	return
 }
 
+// resourceDNSZoneV2TagsURL returns the URL of the tags of the zone, whose
+// resource type depends on the type of the zone.
+func resourceDNSZoneV2TagsURL(dnsClient *golangsdk.ServiceClient, d *schema.ResourceData) string {
+	resourceType := tagsResourceTypeDNSPublicZone
+	if d.Get("zone_type").(string) == "private" {
+		resourceType = tagsResourceTypeDNSPrivateZone
+	}
+	return dnsClient.ServiceURL(dnsClient.ProjectID, resourceType, d.Id(), "tags")
+}
+
 func waitForDNSZone(dnsClient *golangsdk.ServiceClient, zoneId string) resource.StateRefreshFunc {
 	return func() (interface{}, string, error) {
 		zone, err := zones.Get(dnsClient, zoneId).Extract()
@@ -0,0 +1,845 @@
+package huaweicloud
+
+import (
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/blockstorage/v2/volumes"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/flavors"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/huaweicloud/golangsdk"
+)
+
+func resourceEcsInstanceV1() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceEcsInstanceV1Create,
+		Read:   resourceEcsInstanceV1Read,
+		Update: resourceEcsInstanceV1Update,
+		Delete: resourceEcsInstanceV1Delete,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+			Update: schema.DefaultTimeout(30 * time.Minute),
+			Delete: schema.DefaultTimeout(30 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"region": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+			"project_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+			"enterprise_project_id": enterpriseProjectIDSchema(),
+			"tags":                  tagsSchema(),
+			"tags_all":              tagsAllSchema(),
+			"deletion_protection":   deletionProtectionSchema(),
+
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"image_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+				Computed: true,
+			},
+			"image_name": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+				Computed: true,
+			},
+			"flavor_id": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				DefaultFunc: schema.EnvDefaultFunc("OS_FLAVOR_ID", nil),
+			},
+			"flavor_name": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				DefaultFunc: schema.EnvDefaultFunc("OS_FLAVOR_NAME", nil),
+			},
+			"availability_zone": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"vpc_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"nics": &schema.Schema{
+				Type:     schema.TypeList,
+				Required: true,
+				ForceNew: true,
+				MinItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"network_id": &schema.Schema{
+							Type:     schema.TypeString,
+							Required: true,
+							ForceNew: true,
+						},
+						"ip_address": &schema.Schema{
+							Type:     schema.TypeString,
+							Optional: true,
+							ForceNew: true,
+							Computed: true,
+						},
+						"mac_address": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"security_groups": &schema.Schema{
+				Type:     schema.TypeSet,
+				Optional: true,
+				ForceNew: true,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Set:      schema.HashString,
+			},
+			"system_disk_type": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+				Computed: true,
+			},
+			"system_disk_size": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+				ForceNew: true,
+				Computed: true,
+			},
+			"system_disk_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"data_disks": &schema.Schema{
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"type": &schema.Schema{
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"size": &schema.Schema{
+							Type:     schema.TypeInt,
+							Required: true,
+						},
+						"id": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"key_name": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+			"admin_pass": &schema.Schema{
+				Type:      schema.TypeString,
+				Optional:  true,
+				ForceNew:  true,
+				Sensitive: true,
+			},
+			"user_data": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+				// just stash the hash for state & diff comparisons
+				StateFunc: func(v interface{}) string {
+					switch v.(type) {
+					case string:
+						hash := sha1.Sum([]byte(v.(string)))
+						return hex.EncodeToString(hash[:])
+					default:
+						return ""
+					}
+				},
+			},
+			"eip": &schema.Schema{
+				Type:     schema.TypeList,
+				Optional: true,
+				ForceNew: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"type": &schema.Schema{
+							Type:     schema.TypeString,
+							Required: true,
+							ForceNew: true,
+						},
+						"bandwidth_size": &schema.Schema{
+							Type:     schema.TypeInt,
+							Required: true,
+							ForceNew: true,
+						},
+						"bandwidth_share_type": &schema.Schema{
+							Type:     schema.TypeString,
+							Optional: true,
+							ForceNew: true,
+							Default:  "PER",
+						},
+						"bandwidth_charge_mode": &schema.Schema{
+							Type:     schema.TypeString,
+							Optional: true,
+							ForceNew: true,
+							Default:  "traffic",
+							ValidateFunc: func(v interface{}, k string) (ws []string, errors []error) {
+								return ValidateStringList(v, k, []string{"traffic", "bandwidth"})
+							},
+						},
+					},
+				},
+			},
+			"public_ip": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"charging_mode": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+				Default:  "postPaid",
+				ValidateFunc: func(v interface{}, k string) (ws []string, errors []error) {
+					return ValidateStringList(v, k, []string{"postPaid", "prePaid"})
+				},
+			},
+			"period_unit": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+				ValidateFunc: func(v interface{}, k string) (ws []string, errors []error) {
+					return ValidateStringList(v, k, []string{"month", "year"})
+				},
+			},
+			"period": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+				ForceNew: true,
+			},
+			"auto_renew": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				ForceNew: true,
+			},
+			"delete_disks_on_termination": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"delete_eip_on_termination": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"status": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// ecsServer is a server of the ECS v1 API.
+type ecsServer struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Flavor struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"flavor"`
+	Image struct {
+		ID string `json:"id"`
+	} `json:"image"`
+	Addresses        map[string][]ecsServerAddress `json:"addresses"`
+	AvailabilityZone string                        `json:"OS-EXT-AZ:availability_zone"`
+	SecurityGroups   []struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"security_groups"`
+	VolumesAttached []struct {
+		ID        string `json:"id"`
+		BootIndex string `json:"bootIndex"`
+		Device    string `json:"device"`
+	} `json:"os-extended-volumes:volumes_attached"`
+	Metadata            map[string]string `json:"metadata"`
+	KeyName             string            `json:"key_name"`
+	EnterpriseProjectID string            `json:"enterprise_project_id"`
+}
+
+type ecsServerAddress struct {
+	Addr string `json:"addr"`
+	Type string `json:"OS-EXT-IPS:type"`
+	MAC  string `json:"OS-EXT-IPS-MAC:mac_addr"`
+}
+
+func resourceEcsInstanceV1Create(d *schema.ResourceData, meta interface{}) error {
+	config := GetProjectConfig(d, meta.(*Config))
+	region := GetRegion(d, config)
+	computeClient, err := config.computeV2Client(region)
+	if err != nil {
+		return fmt.Errorf("Error creating HuaweiCloud compute client: %s", err)
+	}
+	ecsClient, err := config.computeV1Client(region)
+	if err != nil {
+		return fmt.Errorf("Error creating HuaweiCloud compute v1 client: %s", err)
+	}
+
+	imageId, err := getImageIDFromConfig(computeClient, d)
+	if err != nil {
+		return err
+	}
+	flavorId, err := getFlavorID(computeClient, d)
+	if err != nil {
+		return err
+	}
+
+	createOpts := map[string]interface{}{
+		"name":              d.Get("name").(string),
+		"imageRef":          imageId,
+		"flavorRef":         flavorId,
+		"vpcid":             d.Get("vpc_id").(string),
+		"availability_zone": d.Get("availability_zone").(string),
+		"nics":              resourceEcsInstanceV1Nics(d),
+		"extendparam":       resourceEcsInstanceV1ExtendParam(d),
+	}
+
+	rootVolume := make(map[string]interface{})
+	if v, ok := d.GetOk("system_disk_type"); ok {
+		rootVolume["volumetype"] = v.(string)
+	}
+	if v, ok := d.GetOk("system_disk_size"); ok {
+		rootVolume["size"] = v.(int)
+	}
+	createOpts["root_volume"] = rootVolume
+
+	var dataVolumes []map[string]interface{}
+	for _, v := range d.Get("data_disks").([]interface{}) {
+		disk := v.(map[string]interface{})
+		dataVolumes = append(dataVolumes, map[string]interface{}{
+			"volumetype": disk["type"].(string),
+			"size":       disk["size"].(int),
+		})
+	}
+	if len(dataVolumes) > 0 {
+		createOpts["data_volumes"] = dataVolumes
+	}
+
+	var secGroups []map[string]interface{}
+	for _, id := range d.Get("security_groups").(*schema.Set).List() {
+		secGroups = append(secGroups, map[string]interface{}{"id": id.(string)})
+	}
+	if len(secGroups) > 0 {
+		createOpts["security_groups"] = secGroups
+	}
+
+	if v, ok := d.GetOk("eip"); ok {
+		eip := v.([]interface{})[0].(map[string]interface{})
+		createOpts["publicip"] = map[string]interface{}{
+			"eip": map[string]interface{}{
+				"iptype": eip["type"].(string),
+				"bandwidth": map[string]interface{}{
+					"size":       eip["bandwidth_size"].(int),
+					"sharetype":  eip["bandwidth_share_type"].(string),
+					"chargemode": eip["bandwidth_charge_mode"].(string),
+				},
+			},
+		}
+	}
+
+	if v, ok := d.GetOk("key_name"); ok {
+		createOpts["key_name"] = v.(string)
+	}
+	if v, ok := d.GetOk("user_data"); ok {
+		createOpts["user_data"] = base64.StdEncoding.EncodeToString([]byte(v.(string)))
+	}
+
+	log.Printf("[DEBUG] Create Options: %#v", createOpts)
+
+	// Add the password only after logging the options.
+	if v, ok := d.GetOk("admin_pass"); ok {
+		createOpts["adminPass"] = v.(string)
+	}
+
+	job, err := startECSJob(ecsClient, "POST", ecsClient.ServiceURL("cloudservers"),
+		map[string]interface{}{"server": createOpts})
+	if err != nil {
+		return fmt.Errorf("Error creating HuaweiCloud ECS instance: %s", err)
+	}
+	if len(job.ServerIDs) == 0 {
+		return fmt.Errorf("Error creating HuaweiCloud ECS instance: no server ID in the response")
+	}
+
+	d.SetId(job.ServerIDs[0])
+	log.Printf("[INFO] ECS instance ID: %s", d.Id())
+
+	// Prepaid servers are created by an order, whose servers are waited for.
+	if job.JobID != "" {
+		err = waitForECSJobSuccess(config, ecsClient, job.JobID, d.Timeout(schema.TimeoutCreate))
+	} else {
+		err = waitForECSInstanceActive(config, ecsClient, d.Id(), d.Timeout(schema.TimeoutCreate))
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := updateResourceTags(ecsClient, d, config, ecsClient.ServiceURL("cloudservers", d.Id(), "tags")); err != nil {
+		return err
+	}
+
+	return resourceEcsInstanceV1Read(d, meta)
+}
+
+func resourceEcsInstanceV1Read(d *schema.ResourceData, meta interface{}) error {
+	config := GetProjectConfig(d, meta.(*Config))
+	region := GetRegion(d, config)
+	ecsClient, err := config.computeV1Client(region)
+	if err != nil {
+		return fmt.Errorf("Error creating HuaweiCloud compute v1 client: %s", err)
+	}
+
+	server, err := getECSInstance(ecsClient, d.Id())
+	if err != nil {
+		return CheckDeleted(d, err, "ECS instance")
+	}
+	log.Printf("[DEBUG] Retrieved ECS instance %s: %+v", d.Id(), server)
+
+	d.Set("name", server.Name)
+	d.Set("status", server.Status)
+	d.Set("availability_zone", server.AvailabilityZone)
+	d.Set("flavor_id", server.Flavor.ID)
+	d.Set("flavor_name", server.Flavor.Name)
+	d.Set("key_name", server.KeyName)
+	if server.EnterpriseProjectID != "" {
+		d.Set("enterprise_project_id", server.EnterpriseProjectID)
+	}
+
+	computeClient, err := config.computeV2Client(region)
+	if err != nil {
+		return fmt.Errorf("Error creating HuaweiCloud compute client: %s", err)
+	}
+	if err := setImageInformation(computeClient, &servers.Server{Image: map[string]interface{}{"id": server.Image.ID}}, d); err != nil {
+		return err
+	}
+
+	switch server.Metadata["charging_mode"] {
+	case "0":
+		d.Set("charging_mode", "postPaid")
+	case "1":
+		d.Set("charging_mode", "prePaid")
+	}
+
+	// The fixed addresses of the server are listed in the order of its NICs.
+	var fixedAddresses []ecsServerAddress
+	var publicIP string
+	for _, address := range server.Addresses[d.Get("vpc_id").(string)] {
+		if address.Type == "floating" {
+			publicIP = address.Addr
+		} else {
+			fixedAddresses = append(fixedAddresses, address)
+		}
+	}
+	nics := d.Get("nics").([]interface{})
+	for i, v := range nics {
+		nic := v.(map[string]interface{})
+		if i < len(fixedAddresses) {
+			nic["ip_address"] = fixedAddresses[i].Addr
+			nic["mac_address"] = fixedAddresses[i].MAC
+		}
+	}
+	d.Set("nics", nics)
+	d.Set("public_ip", publicIP)
+
+	var secGroups []string
+	for _, sg := range server.SecurityGroups {
+		secGroups = append(secGroups, sg.ID)
+	}
+	d.Set("security_groups", secGroups)
+
+	if err := resourceEcsInstanceV1ReadDisks(d, config, server); err != nil {
+		return err
+	}
+
+	if err := readResourceTags(ecsClient, d, config, ecsClient.ServiceURL("cloudservers", d.Id(), "tags")); err != nil {
+		return err
+	}
+
+	d.Set("region", region)
+
+	return nil
+}
+
+func resourceEcsInstanceV1Update(d *schema.ResourceData, meta interface{}) error {
+	config := GetProjectConfig(d, meta.(*Config))
+	region := GetRegion(d, config)
+	ecsClient, err := config.computeV1Client(region)
+	if err != nil {
+		return fmt.Errorf("Error creating HuaweiCloud compute v1 client: %s", err)
+	}
+
+	if d.HasChange("name") {
+		updateOpts := map[string]interface{}{
+			"server": map[string]interface{}{"name": d.Get("name").(string)},
+		}
+		_, err := ecsClient.Put(ecsClient.ServiceURL("cloudservers", d.Id()), updateOpts, nil, &golangsdk.RequestOpts{
+			OkCodes: []int{200},
+		})
+		if err != nil {
+			return fmt.Errorf("Error updating HuaweiCloud ECS instance %s: %s", d.Id(), err)
+		}
+	}
+
+	if d.HasChange("flavor_id") || d.HasChange("flavor_name") {
+		if err := resourceEcsInstanceV1Resize(d, config, ecsClient); err != nil {
+			return err
+		}
+	}
+
+	if d.HasChange("data_disks") {
+		if err := resourceEcsInstanceV1UpdateDisks(d, config, ecsClient); err != nil {
+			return err
+		}
+	}
+
+	if d.HasChange("enterprise_project_id") {
+		if err := migrateEnterpriseProject(d, config, epsResourceTypeECS); err != nil {
+			return err
+		}
+	}
+
+	if d.HasChange("tags") {
+		if err := updateResourceTags(ecsClient, d, config, ecsClient.ServiceURL("cloudservers", d.Id(), "tags")); err != nil {
+			return err
+		}
+	}
+
+	return resourceEcsInstanceV1Read(d, meta)
+}
+
+func resourceEcsInstanceV1Delete(d *schema.ResourceData, meta interface{}) error {
+	if err := checkDeletionProtection(d, "ECS instance"); err != nil {
+		return err
+	}
+
+	config := GetProjectConfig(d, meta.(*Config))
+	ecsClient, err := config.computeV1Client(GetRegion(d, config))
+	if err != nil {
+		return fmt.Errorf("Error creating HuaweiCloud compute v1 client: %s", err)
+	}
+
+	deleteOpts := map[string]interface{}{
+		"servers":         []map[string]interface{}{{"id": d.Id()}},
+		"delete_publicip": d.Get("delete_eip_on_termination").(bool),
+		"delete_volume":   d.Get("delete_disks_on_termination").(bool),
+	}
+
+	log.Printf("[DEBUG] Deleting ECS instance %s", d.Id())
+	job, err := startECSJob(ecsClient, "POST", ecsClient.ServiceURL("cloudservers", "delete"), deleteOpts)
+	if err != nil {
+		return CheckDeleted(d, err, fmt.Sprintf("Error deleting HuaweiCloud ECS instance %s", d.Id()))
+	}
+	if err := waitForECSJobSuccess(config, ecsClient, job.JobID, d.Timeout(schema.TimeoutDelete)); err != nil {
+		return err
+	}
+
+	d.SetId("")
+	return nil
+}
+
+func getECSInstance(client *golangsdk.ServiceClient, id string) (*ecsServer, error) {
+	var r struct {
+		Server ecsServer `json:"server"`
+	}
+	_, err := client.Get(client.ServiceURL("cloudservers", id), &r, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &r.Server, nil
+}
+
+func waitForECSInstanceActive(config *Config, client *golangsdk.ServiceClient, id string, timeout time.Duration) error {
+	stateConf := &resource.StateChangeConf{
+		Pending: []string{"BUILD"},
+		Target:  []string{"ACTIVE"},
+		Refresh: func() (interface{}, string, error) {
+			server, err := getECSInstance(client, id)
+			if err != nil {
+				// The servers of an order only exist once it is paid.
+				if _, ok := err.(golangsdk.ErrDefault404); ok {
+					return server, "BUILD", nil
+				}
+				return nil, "", err
+			}
+			return server, server.Status, nil
+		},
+		Timeout:    timeout,
+		Delay:      config.stateRefreshDelay(10 * time.Second),
+		MinTimeout: 3 * time.Second,
+	}
+
+	if _, err := stateConf.WaitForState(); err != nil {
+		return fmt.Errorf("Error waiting for ECS instance %s to become ready: %s", id, err)
+	}
+	return nil
+}
+
+func resourceEcsInstanceV1Nics(d *schema.ResourceData) []map[string]interface{} {
+	var nics []map[string]interface{}
+	for _, v := range d.Get("nics").([]interface{}) {
+		nic := v.(map[string]interface{})
+		opts := map[string]interface{}{"subnet_id": nic["network_id"].(string)}
+		if ip := nic["ip_address"].(string); ip != "" {
+			opts["ip_address"] = ip
+		}
+		nics = append(nics, opts)
+	}
+	return nics
+}
+
+func resourceEcsInstanceV1ExtendParam(d *schema.ResourceData) map[string]interface{} {
+	extendParam := map[string]interface{}{
+		"chargingMode": d.Get("charging_mode").(string),
+	}
+	if d.Get("charging_mode").(string) == "prePaid" {
+		extendParam["periodType"] = d.Get("period_unit").(string)
+		extendParam["periodNum"] = d.Get("period").(int)
+		extendParam["isAutoRenew"] = strconv.FormatBool(d.Get("auto_renew").(bool))
+		extendParam["isAutoPay"] = "true"
+	}
+	if v, ok := d.GetOk("enterprise_project_id"); ok {
+		extendParam["enterprise_project_id"] = v.(string)
+	}
+	return extendParam
+}
+
+// resourceEcsInstanceV1ReadDisks sets the system disk and the data disks from
+// the volumes attached to the server. The data disks are identified by their
+// ID, the data disks of a server being created taking the other volumes in the
+// order of their devices.
+func resourceEcsInstanceV1ReadDisks(d *schema.ResourceData, config *Config, server *ecsServer) error {
+	blockStorageClient, err := config.blockStorageV2Client(GetRegion(d, config))
+	if err != nil {
+		return fmt.Errorf("Error creating HuaweiCloud block storage client: %s", err)
+	}
+
+	attached := server.VolumesAttached
+	sort.Slice(attached, func(i, j int) bool { return attached[i].Device < attached[j].Device })
+
+	disks := d.Get("data_disks").([]interface{})
+	known := make(map[string]bool)
+	for _, v := range disks {
+		known[v.(map[string]interface{})["id"].(string)] = true
+	}
+
+	var dataDiskIDs []string
+	for _, volume := range attached {
+		if volume.BootIndex == "0" {
+			v, err := volumes.Get(blockStorageClient, volume.ID).Extract()
+			if err != nil {
+				return fmt.Errorf("Error retrieving the system disk %s of ECS instance %s: %s", volume.ID, d.Id(), err)
+			}
+			d.Set("system_disk_id", v.ID)
+			d.Set("system_disk_type", v.VolumeType)
+			d.Set("system_disk_size", v.Size)
+		} else if !known[volume.ID] {
+			dataDiskIDs = append(dataDiskIDs, volume.ID)
+		}
+	}
+
+	var dataDisks []map[string]interface{}
+	for _, v := range disks {
+		disk := v.(map[string]interface{})
+		id := disk["id"].(string)
+		if id == "" {
+			if len(dataDiskIDs) == 0 {
+				continue
+			}
+			id, dataDiskIDs = dataDiskIDs[0], dataDiskIDs[1:]
+		}
+
+		volume, err := volumes.Get(blockStorageClient, id).Extract()
+		if err != nil {
+			if _, ok := err.(gophercloud.ErrDefault404); ok {
+				log.Printf("[WARN] Data disk %s of ECS instance %s not found", id, d.Id())
+				continue
+			}
+			return fmt.Errorf("Error retrieving the data disk %s of ECS instance %s: %s", id, d.Id(), err)
+		}
+		dataDisks = append(dataDisks, map[string]interface{}{
+			"id":   volume.ID,
+			"type": volume.VolumeType,
+			"size": volume.Size,
+		})
+	}
+	d.Set("data_disks", dataDisks)
+
+	return nil
+}
+
+func resourceEcsInstanceV1Resize(d *schema.ResourceData, config *Config, ecsClient *golangsdk.ServiceClient) error {
+	flavorId := d.Get("flavor_id").(string)
+	if !d.HasChange("flavor_id") {
+		computeClient, err := config.computeV2Client(GetRegion(d, config))
+		if err != nil {
+			return fmt.Errorf("Error creating HuaweiCloud compute client: %s", err)
+		}
+		flavorId, err = flavors.IDFromName(computeClient, d.Get("flavor_name").(string))
+		if err != nil {
+			return err
+		}
+	}
+
+	resizeOpts := map[string]interface{}{
+		"resize": map[string]interface{}{"flavorRef": flavorId},
+	}
+	log.Printf("[DEBUG] Resizing ECS instance %s to flavor %s", d.Id(), flavorId)
+	job, err := startECSJob(ecsClient, "POST", ecsClient.ServiceURL("cloudservers", d.Id(), "resize"), resizeOpts)
+	if err != nil {
+		return fmt.Errorf("Error resizing HuaweiCloud ECS instance %s: %s", d.Id(), err)
+	}
+	return waitForECSJobSuccess(config, ecsClient, job.JobID, d.Timeout(schema.TimeoutUpdate))
+}
+
+// resourceEcsInstanceV1UpdateDisks detaches and deletes the data disks removed
+// from data_disks, and creates and attaches the data disks added at its end.
+//
+// The IDs of the planned data disks are those of the previous disks at the same
+// index, so the kept disks are matched by their type and size in order
+// instead: removing one of several disks of the same type and size removes the
+// last of them. The type and the size of the existing data disks can't be
+// changed, and disks can't be removed and added at once.
+func resourceEcsInstanceV1UpdateDisks(d *schema.ResourceData, config *Config, ecsClient *golangsdk.ServiceClient) error {
+	o, n := d.GetChange("data_disks")
+	oldDisks, newDisks := o.([]interface{}), n.([]interface{})
+
+	var disks []interface{}
+	var removed []interface{}
+	for _, v := range oldDisks {
+		oldDisk := v.(map[string]interface{})
+		if len(disks) < len(newDisks) {
+			newDisk := newDisks[len(disks)].(map[string]interface{})
+			if newDisk["type"] == oldDisk["type"] && newDisk["size"] == oldDisk["size"] {
+				disks = append(disks, oldDisk)
+				continue
+			}
+		}
+		removed = append(removed, oldDisk)
+	}
+	if len(removed) > 0 && len(disks) < len(newDisks) {
+		return fmt.Errorf("Error updating the data disks of ECS instance %s: the type and the size of "+
+			"a data disk can't be changed, and data disks can't be removed and added at once", d.Id())
+	}
+
+	blockStorageClient, err := config.blockStorageV2Client(GetRegion(d, config))
+	if err != nil {
+		return fmt.Errorf("Error creating HuaweiCloud block storage client: %s", err)
+	}
+
+	for i, v := range removed {
+		// Keep the disks until they are deleted, so that a failed update is
+		// retried.
+		d.Set("data_disks", append(disks, removed[i:]...))
+
+		id := v.(map[string]interface{})["id"].(string)
+		log.Printf("[DEBUG] Detaching data disk %s of ECS instance %s", id, d.Id())
+		job, err := startECSJob(ecsClient, "DELETE", ecsClient.ServiceURL("cloudservers", d.Id(), "detachvolume", id), nil)
+		if err != nil {
+			return fmt.Errorf("Error detaching data disk %s of ECS instance %s: %s", id, d.Id(), err)
+		}
+		if err := waitForECSJobSuccess(config, ecsClient, job.JobID, d.Timeout(schema.TimeoutUpdate)); err != nil {
+			return err
+		}
+
+		if err := volumes.Delete(blockStorageClient, id).ExtractErr(); err != nil {
+			return fmt.Errorf("Error deleting data disk %s of ECS instance %s: %s", id, d.Id(), err)
+		}
+		stateConf := &resource.StateChangeConf{
+			Pending:    []string{"deleting", "available"},
+			Target:     []string{"deleted"},
+			Refresh:    VolumeV2StateRefreshFunc(blockStorageClient, id),
+			Timeout:    d.Timeout(schema.TimeoutUpdate),
+			Delay:      config.stateRefreshDelay(10 * time.Second),
+			MinTimeout: 3 * time.Second,
+		}
+		if _, err := stateConf.WaitForState(); err != nil {
+			return fmt.Errorf("Error waiting for data disk %s of ECS instance %s to be deleted: %s", id, d.Id(), err)
+		}
+	}
+
+	for i := len(disks); i < len(newDisks); i++ {
+		newDisk := newDisks[i].(map[string]interface{})
+		createOpts := &volumes.CreateOpts{
+			Name:             fmt.Sprintf("%s-data-%d", d.Get("name").(string), i),
+			Size:             newDisk["size"].(int),
+			VolumeType:       newDisk["type"].(string),
+			AvailabilityZone: d.Get("availability_zone").(string),
+		}
+		log.Printf("[DEBUG] Creating data disk of ECS instance %s: %#v", d.Id(), createOpts)
+		v, err := volumes.Create(blockStorageClient, createOpts).Extract()
+		if err != nil {
+			return fmt.Errorf("Error creating data disk of ECS instance %s: %s", d.Id(), err)
+		}
+		stateConf := &resource.StateChangeConf{
+			Pending:    []string{"downloading", "creating"},
+			Target:     []string{"available"},
+			Refresh:    VolumeV2StateRefreshFunc(blockStorageClient, v.ID),
+			Timeout:    d.Timeout(schema.TimeoutUpdate),
+			Delay:      config.stateRefreshDelay(10 * time.Second),
+			MinTimeout: 3 * time.Second,
+		}
+		if _, err := stateConf.WaitForState(); err != nil {
+			return fmt.Errorf("Error waiting for data disk %s of ECS instance %s to become ready: %s", v.ID, d.Id(), err)
+		}
+
+		attachOpts := map[string]interface{}{
+			"volumeAttachment": map[string]interface{}{"volumeId": v.ID},
+		}
+		job, err := startECSJob(ecsClient, "POST", ecsClient.ServiceURL("cloudservers", d.Id(), "attachvolume"), attachOpts)
+		if err != nil {
+			return fmt.Errorf("Error attaching data disk %s to ECS instance %s: %s", v.ID, d.Id(), err)
+		}
+
+		// Keep the disk even if the attachment fails, so that it is deleted
+		// with the instance.
+		disks = append(disks, map[string]interface{}{
+			"id":   v.ID,
+			"type": v.VolumeType,
+			"size": v.Size,
+		})
+		d.Set("data_disks", disks)
+
+		if err := waitForECSJobSuccess(config, ecsClient, job.JobID, d.Timeout(schema.TimeoutUpdate)); err != nil {
+			return err
+		}
+	}
+
+	d.Set("data_disks", disks)
+	return nil
+}
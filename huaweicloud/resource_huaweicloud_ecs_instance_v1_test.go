@@ -0,0 +1,231 @@
+package huaweicloud
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/fakecloud"
+)
+
+func TestFakeCloudEcsInstanceV1Lifecycle(t *testing.T) {
+	t.Parallel()
+
+	srv, providerConfig := testFakeCloud(t)
+	defer srv.Close()
+
+	networkID := srv.List("network/networks")[0]["id"].(string)
+	vpcID := srv.Add("network/routers", map[string]interface{}{"name": "fake-vpc"})
+
+	r := resourceEcsInstanceV1()
+	raw := map[string]interface{}{
+		"name":              "instance_1",
+		"image_id":          testFakeCloudImageID,
+		"flavor_id":         "s3.small.1",
+		"availability_zone": fakecloud.AvailabilityZone,
+		"vpc_id":            vpcID,
+		"nics": []interface{}{
+			map[string]interface{}{"network_id": networkID},
+		},
+		"system_disk_type": "SSD",
+		"system_disk_size": 50,
+		"data_disks": []interface{}{
+			map[string]interface{}{"type": "SATA", "size": 10},
+		},
+		"eip": []interface{}{
+			map[string]interface{}{"type": "5_bgp", "bandwidth_size": 5},
+		},
+		"tags": map[string]interface{}{"foo": "bar"},
+	}
+
+	state := testFakeCloudApply(t, r, nil, raw, providerConfig)
+	if _, ok := srv.Get("compute/servers", state.ID); !ok {
+		t.Fatalf("Instance %s not found in the fake cloud", state.ID)
+	}
+	systemDisk, ok := srv.Get("evs/volumes", state.Attributes["system_disk_id"])
+	if !ok || systemDisk["volume_type"] != "SSD" || systemDisk["size"] != float64(50) {
+		t.Fatalf("Unexpected system disk: %v", systemDisk)
+	}
+	firstDiskID := state.Attributes["data_disks.0.id"]
+	if _, ok := srv.Get("evs/volumes", firstDiskID); !ok {
+		t.Fatalf("Data disk %q not found in the fake cloud", firstDiskID)
+	}
+	if state.Attributes["public_ip"] == "" || state.Attributes["nics.0.ip_address"] == "" {
+		t.Fatalf("Expected the addresses of the instance to be read, got %v", state.Attributes)
+	}
+	if tags := srv.Tags("compute/servers", state.ID); tags["foo"] != "bar" {
+		t.Fatalf("Unexpected tags: %v", tags)
+	}
+
+	c, err := config.NewRawConfig(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff, err := r.Diff(state, terraform.NewResourceConfig(c)); err != nil || diff != nil {
+		t.Fatalf("Expected no changes after the creation, got %v, %v", diff, err)
+	}
+
+	// The instance is resized and a data disk is attached in place.
+	raw["flavor_id"] = "s3.medium.2"
+	raw["data_disks"] = []interface{}{
+		map[string]interface{}{"type": "SATA", "size": 10},
+		map[string]interface{}{"type": "SSD", "size": 20},
+	}
+	updated := testFakeCloudApply(t, r, state, raw, providerConfig)
+	if updated.ID != state.ID {
+		t.Fatalf("Expected the instance to be updated in place, got %s", updated.ID)
+	}
+	server, _ := srv.Get("compute/servers", state.ID)
+	if flavor := server["flavor"].(map[string]interface{}); flavor["id"] != "s3.medium.2" {
+		t.Fatalf("Instance not resized: %v", flavor)
+	}
+	secondDiskID := updated.Attributes["data_disks.1.id"]
+	if disk, ok := srv.Get("evs/volumes", secondDiskID); !ok || disk["status"] != "in-use" {
+		t.Fatalf("Data disk %q not attached: %v", secondDiskID, disk)
+	}
+
+	// The removed data disk is detached and deleted.
+	raw["data_disks"] = []interface{}{
+		map[string]interface{}{"type": "SATA", "size": 10},
+	}
+	state = testFakeCloudApply(t, r, updated, raw, providerConfig)
+	if _, ok := srv.Get("evs/volumes", secondDiskID); ok {
+		t.Fatalf("Data disk %s still exists", secondDiskID)
+	}
+
+	// Changing an existing data disk fails.
+	raw["data_disks"] = []interface{}{
+		map[string]interface{}{"type": "SATA", "size": 20},
+	}
+	c, err = config.NewRawConfig(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	diff, err := r.Diff(state, terraform.NewResourceConfig(c))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Apply(state, diff, providerConfig); err == nil {
+		t.Fatal("Expected an error when resizing a data disk")
+	}
+
+	// The system disk and the EIP are deleted with the instance, the data
+	// disks are kept.
+	testFakeCloudDestroy(t, r, state, providerConfig)
+	if _, ok := srv.Get("compute/servers", state.ID); ok {
+		t.Fatalf("Instance %s still exists", state.ID)
+	}
+	if _, ok := srv.Get("evs/volumes", state.Attributes["system_disk_id"]); ok {
+		t.Fatal("Expected the system disk to be deleted")
+	}
+	if _, ok := srv.Get("evs/volumes", firstDiskID); !ok {
+		t.Fatal("Expected the data disk to be kept")
+	}
+	if ips := srv.List("vpc/publicips"); len(ips) != 0 {
+		t.Fatalf("Expected the EIP to be deleted, got %v", ips)
+	}
+}
+
+func TestFakeCloudEcsInstanceV1RemoveDataDisk(t *testing.T) {
+	t.Parallel()
+
+	srv, providerConfig := testFakeCloud(t)
+	defer srv.Close()
+
+	networkID := srv.List("network/networks")[0]["id"].(string)
+	vpcID := srv.Add("network/routers", map[string]interface{}{"name": "fake-vpc"})
+
+	r := resourceEcsInstanceV1()
+	raw := map[string]interface{}{
+		"name":              "instance_1",
+		"image_id":          testFakeCloudImageID,
+		"flavor_id":         "s3.small.1",
+		"availability_zone": fakecloud.AvailabilityZone,
+		"vpc_id":            vpcID,
+		"nics": []interface{}{
+			map[string]interface{}{"network_id": networkID},
+		},
+		"data_disks": []interface{}{
+			map[string]interface{}{"type": "SATA", "size": 10},
+			map[string]interface{}{"type": "SSD", "size": 20},
+			map[string]interface{}{"type": "SATA", "size": 30},
+		},
+	}
+	state := testFakeCloudApply(t, r, nil, raw, providerConfig)
+	firstDiskID := state.Attributes["data_disks.0.id"]
+	secondDiskID := state.Attributes["data_disks.1.id"]
+	thirdDiskID := state.Attributes["data_disks.2.id"]
+
+	// The disk removed from the middle of the list is deleted, not the last.
+	raw["data_disks"] = []interface{}{
+		map[string]interface{}{"type": "SATA", "size": 10},
+		map[string]interface{}{"type": "SATA", "size": 30},
+	}
+	state = testFakeCloudApply(t, r, state, raw, providerConfig)
+	if _, ok := srv.Get("evs/volumes", secondDiskID); ok {
+		t.Fatalf("Data disk %s still exists", secondDiskID)
+	}
+	if disk, ok := srv.Get("evs/volumes", thirdDiskID); !ok || disk["status"] != "in-use" {
+		t.Fatalf("Data disk %s not kept: %v", thirdDiskID, disk)
+	}
+	if state.Attributes["data_disks.0.id"] != firstDiskID || state.Attributes["data_disks.1.id"] != thirdDiskID {
+		t.Fatalf("Unexpected data disks: %v", state.Attributes)
+	}
+
+	// Removing and adding data disks at once fails before changing them.
+	raw["data_disks"] = []interface{}{
+		map[string]interface{}{"type": "SATA", "size": 30},
+		map[string]interface{}{"type": "SSD", "size": 40},
+	}
+	c, err := config.NewRawConfig(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	diff, err := r.Diff(state, terraform.NewResourceConfig(c))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Apply(state, diff, providerConfig); err == nil {
+		t.Fatal("Expected an error when removing and adding data disks")
+	}
+	if _, ok := srv.Get("evs/volumes", firstDiskID); !ok {
+		t.Fatalf("Data disk %s was deleted", firstDiskID)
+	}
+}
+
+func TestFakeCloudEcsInstanceV1Delete(t *testing.T) {
+	t.Parallel()
+
+	srv, providerConfig := testFakeCloud(t)
+	defer srv.Close()
+
+	networkID := srv.List("network/networks")[0]["id"].(string)
+	vpcID := srv.Add("network/routers", map[string]interface{}{"name": "fake-vpc"})
+
+	r := resourceEcsInstanceV1()
+	raw := map[string]interface{}{
+		"name":              "instance_1",
+		"image_id":          testFakeCloudImageID,
+		"flavor_id":         "s3.small.1",
+		"availability_zone": fakecloud.AvailabilityZone,
+		"vpc_id":            vpcID,
+		"nics": []interface{}{
+			map[string]interface{}{"network_id": networkID},
+		},
+		"deletion_protection": true,
+	}
+	state := testFakeCloudApply(t, r, nil, raw, providerConfig)
+
+	if _, err := r.Apply(state, &terraform.InstanceDiff{Destroy: true}, providerConfig); err == nil {
+		t.Fatal("Expected the protected instance not to be deleted")
+	}
+	if _, ok := srv.Get("compute/servers", state.ID); !ok {
+		t.Fatalf("Protected instance %s was deleted", state.ID)
+	}
+
+	// An instance deleted outside of Terraform is destroyed without error.
+	raw["deletion_protection"] = false
+	state = testFakeCloudApply(t, r, state, raw, providerConfig)
+	srv.Delete("compute/servers", state.ID)
+	testFakeCloudDestroy(t, r, state, providerConfig)
+}
@@ -173,6 +173,11 @@ func resourceELBLoadBalancer() *schema.Resource {
 				Type:     schema.TypeString,
 				Computed: true,
 			},
+
+			"enterprise_project_id": enterpriseProjectIDSchema(),
+			"tags":                  tagsSchema(),
+			"tags_all":              tagsAllSchema(),
+			"deletion_protection":   deletionProtectionSchema(),
 		},
 	}
 }
@@ -245,6 +250,14 @@ func resourceELBLoadBalancerCreate(d *schema.ResourceData, meta interface{}) err
 	// If all has been successful, set the ID on the resource
 	d.SetId(eid.(string))
 
+	if err := migrateEnterpriseProjectOnCreate(d, config, epsResourceTypeELB); err != nil {
+		return err
+	}
+
+	if err := updateResourceTags(networkingClient, d, config, networkingClient.ServiceURL(networkingClient.ProjectID, "loadbalancers", d.Id(), "tags")); err != nil {
+		return err
+	}
+
 	return resourceELBLoadBalancerRead(d, meta)
 }
 
@@ -255,13 +268,23 @@ func resourceELBLoadBalancerRead(d *schema.ResourceData, meta interface{}) error
 		return fmt.Errorf("Error creating HuaweiCloud networking client: %s", err)
 	}
 
-	lb, err := loadbalancers.Get(networkingClient, d.Id()).Extract()
+	result := loadbalancers.Get(networkingClient, d.Id())
+	lb, err := result.Extract()
 	if err != nil {
 		return CheckDeleted(d, err, "loadbalancer")
 	}
 	log.Printf("[DEBUG] Retrieved %s %s: %#v", nameELBLB, d.Id(), lb)
 
-	return refreshResourceData(lb, d, nil)
+	if err := refreshResourceData(lb, d, nil); err != nil {
+		return err
+	}
+	setEnterpriseProjectID(d, result.Body, "")
+
+	if err := readResourceTags(networkingClient, d, config, networkingClient.ServiceURL(networkingClient.ProjectID, "loadbalancers", d.Id(), "tags")); err != nil {
+		return err
+	}
+
+	return nil
 }
 
 func resourceELBLoadBalancerUpdate(d *schema.ResourceData, meta interface{}) error {
@@ -273,6 +296,25 @@ func resourceELBLoadBalancerUpdate(d *schema.ResourceData, meta interface{}) err
 
 	lbId := d.Id()
 
+	if d.HasChange("enterprise_project_id") {
+		if err := migrateEnterpriseProject(d, config, epsResourceTypeELB); err != nil {
+			return err
+		}
+	}
+
+	if d.HasChange("tags") {
+		if err := updateResourceTags(networkingClient, d, config, networkingClient.ServiceURL(networkingClient.ProjectID, "loadbalancers", d.Id(), "tags")); err != nil {
+			return err
+		}
+	}
+
+	// Only the name, the description, the bandwidth and the admin state are
+	// updated by the update job.
+	if !d.HasChange("name") && !d.HasChange("description") &&
+		!d.HasChange("bandwidth") && !d.HasChange("admin_state_up") {
+		return resourceELBLoadBalancerRead(d, meta)
+	}
+
 	var updateOpts loadbalancers.UpdateOpts
 	not_pass_param, err := buildUpdateParam(&updateOpts, d, nil)
 	if err != nil {
@@ -310,6 +352,10 @@ func resourceELBLoadBalancerUpdate(d *schema.ResourceData, meta interface{}) err
 }
 
 func resourceELBLoadBalancerDelete(d *schema.ResourceData, meta interface{}) error {
+	if err := checkDeletionProtection(d, "load balancer"); err != nil {
+		return err
+	}
+
 	config := meta.(*Config)
 	networkingClient, err := chooseELBClient(d, config)
 	if err != nil {
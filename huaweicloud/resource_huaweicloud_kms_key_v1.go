@@ -78,6 +78,9 @@ func resourceKmsKeyV1() *schema.Resource {
 				Type:     schema.TypeString,
 				Optional: true,
 			},
+			"tags":                tagsSchema(),
+			"tags_all":            tagsAllSchema(),
+			"deletion_protection": deletionProtectionSchema(),
 		},
 	}
 }
@@ -136,6 +139,10 @@ func resourceKmsKeyV1Create(d *schema.ResourceData, meta interface{}) error {
 	d.SetId(v.KeyID)
 	d.Set("key_id", v.KeyID)
 
+	if err := updateResourceTags(kmsKeyV1Client, d, config, kmsKeyV1Client.ServiceURL(kmsKeyV1Client.ProjectID, "kms", d.Id(), "tags")); err != nil {
+		return err
+	}
+
 	return resourceKmsKeyV1Read(d, meta)
 }
 
@@ -171,6 +178,10 @@ func resourceKmsKeyV1Read(d *schema.ResourceData, meta interface{}) error {
 	d.Set("expiration_time", v.ExpirationTime)
 	d.Set("origin", v.Origin)
 
+	if err := readResourceTags(kmsKeyV1Client, d, config, kmsKeyV1Client.ServiceURL(kmsKeyV1Client.ProjectID, "kms", d.Id(), "tags")); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -230,10 +241,20 @@ func resourceKmsKeyV1Update(d *schema.ResourceData, meta interface{}) error {
 		}
 	}
 
+	if d.HasChange("tags") {
+		if err := updateResourceTags(kmsKeyV1Client, d, config, kmsKeyV1Client.ServiceURL(kmsKeyV1Client.ProjectID, "kms", d.Id(), "tags")); err != nil {
+			return err
+		}
+	}
+
 	return resourceKmsKeyV1Read(d, meta)
 }
 
 func resourceKmsKeyV1Delete(d *schema.ResourceData, meta interface{}) error {
+	if err := checkDeletionProtection(d, "key"); err != nil {
+		return err
+	}
+
 	config := meta.(*Config)
 	kmsKeyV1Client, err := config.kmsKeyV1Client(GetRegion(d, config))
 	if err != nil {
@@ -31,6 +31,14 @@ func resourceNatGatewayV2() *schema.Resource {
 				Computed: true,
 				ForceNew: true,
 			},
+			"project_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+			"enterprise_project_id": enterpriseProjectIDSchema(),
+			"tags":                  tagsSchema(),
+			"tags_all":              tagsAllSchema(),
 			"name": &schema.Schema{
 				Type:     schema.TypeString,
 				Required: true,
@@ -69,19 +77,22 @@ func resourceNatGatewayV2() *schema.Resource {
 }
 
 func resourceNatGatewayV2Create(d *schema.ResourceData, meta interface{}) error {
-	config := meta.(*Config)
+	config := GetProjectConfig(d, meta.(*Config))
 	natV2Client, err := config.natV2Client(GetRegion(d, config))
 	if err != nil {
 		return fmt.Errorf("Error creating HuaweiCloud nat client: %s", err)
 	}
 
-	createOpts := &natgateways.CreateOpts{
-		Name:              d.Get("name").(string),
-		Description:       d.Get("description").(string),
-		Spec:              d.Get("spec").(string),
-		TenantID:          d.Get("tenant_id").(string),
-		RouterID:          d.Get("router_id").(string),
-		InternalNetworkID: d.Get("internal_network_id").(string),
+	createOpts := NatGatewayCreateOpts{
+		natgateways.CreateOpts{
+			Name:              d.Get("name").(string),
+			Description:       d.Get("description").(string),
+			Spec:              d.Get("spec").(string),
+			TenantID:          d.Get("tenant_id").(string),
+			RouterID:          d.Get("router_id").(string),
+			InternalNetworkID: d.Get("internal_network_id").(string),
+		},
+		d.Get("enterprise_project_id").(string),
 	}
 
 	log.Printf("[DEBUG] Create Options: %#v", createOpts)
@@ -107,17 +118,22 @@ func resourceNatGatewayV2Create(d *schema.ResourceData, meta interface{}) error
 
 	d.SetId(natGateway.ID)
 
+	if err := updateResourceTags(natV2Client, d, config, natV2Client.ServiceURL(natV2Client.ProjectID, "nat_gateways", d.Id(), "tags")); err != nil {
+		return err
+	}
+
 	return resourceNatGatewayV2Read(d, meta)
 }
 
 func resourceNatGatewayV2Read(d *schema.ResourceData, meta interface{}) error {
-	config := meta.(*Config)
+	config := GetProjectConfig(d, meta.(*Config))
 	natV2Client, err := config.natV2Client(GetRegion(d, config))
 	if err != nil {
 		return fmt.Errorf("Error creating HuaweiCloud nat client: %s", err)
 	}
 
-	natGateway, err := natgateways.Get(natV2Client, d.Id()).Extract()
+	result := natgateways.Get(natV2Client, d.Id())
+	natGateway, err := result.Extract()
 	if err != nil {
 		return CheckDeleted(d, err, "Nat Gateway")
 	}
@@ -128,6 +144,11 @@ func resourceNatGatewayV2Read(d *schema.ResourceData, meta interface{}) error {
 	d.Set("router_id", natGateway.RouterID)
 	d.Set("internal_network_id", natGateway.InternalNetworkID)
 	d.Set("tenant_id", natGateway.TenantID)
+	setEnterpriseProjectID(d, result.Body, "nat_gateway")
+
+	if err := readResourceTags(natV2Client, d, config, natV2Client.ServiceURL(natV2Client.ProjectID, "nat_gateways", d.Id(), "tags")); err != nil {
+		return err
+	}
 
 	d.Set("region", GetRegion(d, config))
 
@@ -135,7 +156,7 @@ func resourceNatGatewayV2Read(d *schema.ResourceData, meta interface{}) error {
 }
 
 func resourceNatGatewayV2Update(d *schema.ResourceData, meta interface{}) error {
-	config := meta.(*Config)
+	config := GetProjectConfig(d, meta.(*Config))
 	natV2Client, err := config.natV2Client(GetRegion(d, config))
 	if err != nil {
 		return fmt.Errorf("Error creating HuaweiCloud nat client: %s", err)
@@ -153,18 +174,32 @@ func resourceNatGatewayV2Update(d *schema.ResourceData, meta interface{}) error
 		updateOpts.Spec = d.Get("spec").(string)
 	}
 
-	log.Printf("[DEBUG] Update Options: %#v", updateOpts)
+	if updateOpts != (natgateways.UpdateOpts{}) {
+		log.Printf("[DEBUG] Update Options: %#v", updateOpts)
 
-	_, err = natgateways.Update(natV2Client, d.Id(), updateOpts).Extract()
-	if err != nil {
-		return fmt.Errorf("Error updating Nat Gateway: %s", err)
+		_, err = natgateways.Update(natV2Client, d.Id(), updateOpts).Extract()
+		if err != nil {
+			return fmt.Errorf("Error updating Nat Gateway: %s", err)
+		}
+	}
+
+	if d.HasChange("enterprise_project_id") {
+		if err := migrateEnterpriseProject(d, config, epsResourceTypeNatGateway); err != nil {
+			return err
+		}
+	}
+
+	if d.HasChange("tags") {
+		if err := updateResourceTags(natV2Client, d, config, natV2Client.ServiceURL(natV2Client.ProjectID, "nat_gateways", d.Id(), "tags")); err != nil {
+			return err
+		}
 	}
 
 	return resourceNatGatewayV2Read(d, meta)
 }
 
 func resourceNatGatewayV2Delete(d *schema.ResourceData, meta interface{}) error {
-	config := meta.(*Config)
+	config := GetProjectConfig(d, meta.(*Config))
 	natV2Client, err := config.natV2Client(GetRegion(d, config))
 	if err != nil {
 		return fmt.Errorf("Error creating HuaweiCloud nat client: %s", err)
@@ -30,6 +30,11 @@ func resourceNatSnatRuleV2() *schema.Resource {
 				Computed: true,
 				ForceNew: true,
 			},
+			"project_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
 			"nat_gateway_id": &schema.Schema{
 				Type:     schema.TypeString,
 				Required: true,
@@ -50,7 +55,7 @@ func resourceNatSnatRuleV2() *schema.Resource {
 }
 
 func resourceNatSnatRuleV2Create(d *schema.ResourceData, meta interface{}) error {
-	config := meta.(*Config)
+	config := GetProjectConfig(d, meta.(*Config))
 	natV2Client, err := config.natV2Client(GetRegion(d, config))
 	if err != nil {
 		return fmt.Errorf("Error creating HuaweiCloud nat client: %s", err)
@@ -89,7 +94,7 @@ func resourceNatSnatRuleV2Create(d *schema.ResourceData, meta interface{}) error
 }
 
 func resourceNatSnatRuleV2Read(d *schema.ResourceData, meta interface{}) error {
-	config := meta.(*Config)
+	config := GetProjectConfig(d, meta.(*Config))
 	natV2Client, err := config.natV2Client(GetRegion(d, config))
 	if err != nil {
 		return fmt.Errorf("Error creating HuaweiCloud nat client: %s", err)
@@ -110,7 +115,7 @@ func resourceNatSnatRuleV2Read(d *schema.ResourceData, meta interface{}) error {
 }
 
 func resourceNatSnatRuleV2Delete(d *schema.ResourceData, meta interface{}) error {
-	config := meta.(*Config)
+	config := GetProjectConfig(d, meta.(*Config))
 	natV2Client, err := config.natV2Client(GetRegion(d, config))
 	if err != nil {
 		return fmt.Errorf("Error creating HuaweiCloud nat client: %s", err)
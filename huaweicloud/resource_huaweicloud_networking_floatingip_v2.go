@@ -36,6 +36,11 @@ func resourceNetworkingFloatingIPV2() *schema.Resource {
 				Computed: true,
 				ForceNew: true,
 			},
+			"project_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
 			"address": &schema.Schema{
 				Type:     schema.TypeString,
 				Computed: true,
@@ -72,7 +77,7 @@ func resourceNetworkingFloatingIPV2() *schema.Resource {
 }
 
 func resourceNetworkFloatingIPV2Create(d *schema.ResourceData, meta interface{}) error {
-	config := meta.(*Config)
+	config := GetProjectConfig(d, meta.(*Config))
 	networkingClient, err := config.networkingV2Client(GetRegion(d, config))
 	if err != nil {
 		return fmt.Errorf("Error creating HuaweiCloud network client: %s", err)
@@ -122,7 +127,7 @@ func resourceNetworkFloatingIPV2Create(d *schema.ResourceData, meta interface{})
 }
 
 func resourceNetworkFloatingIPV2Read(d *schema.ResourceData, meta interface{}) error {
-	config := meta.(*Config)
+	config := GetProjectConfig(d, meta.(*Config))
 	networkingClient, err := config.networkingV2Client(GetRegion(d, config))
 	if err != nil {
 		return fmt.Errorf("Error creating HuaweiCloud network client: %s", err)
@@ -149,7 +154,7 @@ func resourceNetworkFloatingIPV2Read(d *schema.ResourceData, meta interface{}) e
 }
 
 func resourceNetworkFloatingIPV2Update(d *schema.ResourceData, meta interface{}) error {
-	config := meta.(*Config)
+	config := GetProjectConfig(d, meta.(*Config))
 	networkingClient, err := config.networkingV2Client(GetRegion(d, config))
 	if err != nil {
 		return fmt.Errorf("Error creating HuaweiCloud network client: %s", err)
@@ -173,7 +178,7 @@ func resourceNetworkFloatingIPV2Update(d *schema.ResourceData, meta interface{})
 }
 
 func resourceNetworkFloatingIPV2Delete(d *schema.ResourceData, meta interface{}) error {
-	config := meta.(*Config)
+	config := GetProjectConfig(d, meta.(*Config))
 	networkingClient, err := config.networkingV2Client(GetRegion(d, config))
 	if err != nil {
 		return fmt.Errorf("Error creating HuaweiCloud network client: %s", err)
@@ -198,7 +203,7 @@ func resourceNetworkFloatingIPV2Delete(d *schema.ResourceData, meta interface{})
 }
 
 func getNetworkID(d *schema.ResourceData, meta interface{}, networkName string) (string, error) {
-	config := meta.(*Config)
+	config := GetProjectConfig(d, meta.(*Config))
 	networkingClient, err := config.networkingV2Client(GetRegion(d, config))
 	if err != nil {
 		return "", fmt.Errorf("Error creating HuaweiCloud network client: %s", err)
@@ -228,7 +233,7 @@ func getNetworkID(d *schema.ResourceData, meta interface{}, networkName string)
 }
 
 func getNetworkName(d *schema.ResourceData, meta interface{}, networkID string) (string, error) {
-	config := meta.(*Config)
+	config := GetProjectConfig(d, meta.(*Config))
 	networkingClient, err := config.networkingV2Client(GetRegion(d, config))
 	if err != nil {
 		return "", fmt.Errorf("Error creating HuaweiCloud network client: %s", err)
@@ -36,6 +36,13 @@ func resourceNetworkingNetworkV2() *schema.Resource {
 				ForceNew: true,
 				Computed: true,
 			},
+			"project_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+			"tags":     tagsSchema(),
+			"tags_all": tagsAllSchema(),
 			"name": &schema.Schema{
 				Type:     schema.TypeString,
 				Optional: true,
@@ -100,7 +107,7 @@ func resourceNetworkingNetworkV2() *schema.Resource {
 }
 
 func resourceNetworkingNetworkV2Create(d *schema.ResourceData, meta interface{}) error {
-	config := meta.(*Config)
+	config := GetProjectConfig(d, meta.(*Config))
 	networkingClient, err := config.networkingV2Client(GetRegion(d, config))
 	if err != nil {
 		return fmt.Errorf("Error creating HuaweiCloud networking client: %s", err)
@@ -161,7 +168,7 @@ func resourceNetworkingNetworkV2Create(d *schema.ResourceData, meta interface{})
 		Target:     []string{"ACTIVE"},
 		Refresh:    waitForNetworkActive(networkingClient, n.ID),
 		Timeout:    d.Timeout(schema.TimeoutCreate),
-		Delay:      5 * time.Second,
+		Delay:      config.stateRefreshDelay(5 * time.Second),
 		MinTimeout: 3 * time.Second,
 	}
 
@@ -169,11 +176,19 @@ func resourceNetworkingNetworkV2Create(d *schema.ResourceData, meta interface{})
 
 	d.SetId(n.ID)
 
+	vpcClient, err := config.vpcV2Client(GetRegion(d, config))
+	if err != nil {
+		return fmt.Errorf("Error creating HuaweiCloud VPC client: %s", err)
+	}
+	if err := updateResourceTags(vpcClient, d, config, vpcClient.ServiceURL(vpcClient.ProjectID, "subnets", d.Id(), "tags")); err != nil {
+		return err
+	}
+
 	return resourceNetworkingNetworkV2Read(d, meta)
 }
 
 func resourceNetworkingNetworkV2Read(d *schema.ResourceData, meta interface{}) error {
-	config := meta.(*Config)
+	config := GetProjectConfig(d, meta.(*Config))
 	networkingClient, err := config.networkingV2Client(GetRegion(d, config))
 	if err != nil {
 		return fmt.Errorf("Error creating HuaweiCloud networking client: %s", err)
@@ -196,11 +211,19 @@ func resourceNetworkingNetworkV2Read(d *schema.ResourceData, meta interface{}) e
 		log.Printf("[DEBUG] unable to set availability_zone_hints: %s", err)
 	}
 
+	vpcClient, err := config.vpcV2Client(GetRegion(d, config))
+	if err != nil {
+		return fmt.Errorf("Error creating HuaweiCloud VPC client: %s", err)
+	}
+	if err := readResourceTags(vpcClient, d, config, vpcClient.ServiceURL(vpcClient.ProjectID, "subnets", d.Id(), "tags")); err != nil {
+		return err
+	}
+
 	return nil
 }
 
 func resourceNetworkingNetworkV2Update(d *schema.ResourceData, meta interface{}) error {
-	config := meta.(*Config)
+	config := GetProjectConfig(d, meta.(*Config))
 	networkingClient, err := config.networkingV2Client(GetRegion(d, config))
 	if err != nil {
 		return fmt.Errorf("Error creating HuaweiCloud networking client: %s", err)
@@ -238,11 +261,21 @@ func resourceNetworkingNetworkV2Update(d *schema.ResourceData, meta interface{})
 		return fmt.Errorf("Error updating HuaweiCloud Neutron Network: %s", err)
 	}
 
+	if d.HasChange("tags") {
+		vpcClient, err := config.vpcV2Client(GetRegion(d, config))
+		if err != nil {
+			return fmt.Errorf("Error creating HuaweiCloud VPC client: %s", err)
+		}
+		if err := updateResourceTags(vpcClient, d, config, vpcClient.ServiceURL(vpcClient.ProjectID, "subnets", d.Id(), "tags")); err != nil {
+			return err
+		}
+	}
+
 	return resourceNetworkingNetworkV2Read(d, meta)
 }
 
 func resourceNetworkingNetworkV2Delete(d *schema.ResourceData, meta interface{}) error {
-	config := meta.(*Config)
+	config := GetProjectConfig(d, meta.(*Config))
 	networkingClient, err := config.networkingV2Client(GetRegion(d, config))
 	if err != nil {
 		return fmt.Errorf("Error creating HuaweiCloud networking client: %s", err)
@@ -253,7 +286,7 @@ func resourceNetworkingNetworkV2Delete(d *schema.ResourceData, meta interface{})
 		Target:     []string{"DELETED"},
 		Refresh:    waitForNetworkDelete(networkingClient, d.Id()),
 		Timeout:    d.Timeout(schema.TimeoutDelete),
-		Delay:      5 * time.Second,
+		Delay:      config.stateRefreshDelay(5 * time.Second),
 		MinTimeout: 3 * time.Second,
 	}
 
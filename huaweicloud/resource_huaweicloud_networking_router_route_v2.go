@@ -27,6 +27,11 @@ func resourceNetworkingRouterRouteV2() *schema.Resource {
 				Computed: true,
 				ForceNew: true,
 			},
+			"project_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
 			"router_id": &schema.Schema{
 				Type:     schema.TypeString,
 				Required: true,
@@ -55,7 +60,7 @@ func resourceNetworkingRouterRouteV2Create(d *schema.ResourceData, meta interfac
 	var destCidr string = d.Get("destination_cidr").(string)
 	var nextHop string = d.Get("next_hop").(string)
 
-	config := meta.(*Config)
+	config := GetProjectConfig(d, meta.(*Config))
 	networkingClient, err := config.networkingV2Client(GetRegion(d, config))
 	if err != nil {
 		return fmt.Errorf("Error creating HuaweiCloud networking client: %s", err)
@@ -113,7 +118,7 @@ func resourceNetworkingRouterRouteV2Read(d *schema.ResourceData, meta interface{
 
 	routerId := d.Get("router_id").(string)
 
-	config := meta.(*Config)
+	config := GetProjectConfig(d, meta.(*Config))
 	networkingClient, err := config.networkingV2Client(GetRegion(d, config))
 	if err != nil {
 		return fmt.Errorf("Error creating HuaweiCloud networking client: %s", err)
@@ -174,7 +179,7 @@ func resourceNetworkingRouterRouteV2Delete(d *schema.ResourceData, meta interfac
 	osMutexKV.Lock(routerId)
 	defer osMutexKV.Unlock(routerId)
 
-	config := meta.(*Config)
+	config := GetProjectConfig(d, meta.(*Config))
 
 	networkingClient, err := config.networkingV2Client(GetRegion(d, config))
 	if err != nil {
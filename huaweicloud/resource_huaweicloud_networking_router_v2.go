@@ -34,6 +34,13 @@ func resourceNetworkingRouterV2() *schema.Resource {
 				ForceNew: true,
 				Computed: true,
 			},
+			"project_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+			"tags":     tagsSchema(),
+			"tags_all": tagsAllSchema(),
 			"name": &schema.Schema{
 				Type:     schema.TypeString,
 				Optional: true,
@@ -104,7 +111,7 @@ func resourceNetworkingRouterV2() *schema.Resource {
 }
 
 func resourceNetworkingRouterV2Create(d *schema.ResourceData, meta interface{}) error {
-	config := meta.(*Config)
+	config := GetProjectConfig(d, meta.(*Config))
 	networkingClient, err := config.networkingV2Client(GetRegion(d, config))
 	if err != nil {
 		return fmt.Errorf("Error creating HuaweiCloud networking client: %s", err)
@@ -179,11 +186,19 @@ func resourceNetworkingRouterV2Create(d *schema.ResourceData, meta interface{})
 
 	d.SetId(n.ID)
 
+	vpcClient, err := config.vpcV2Client(GetRegion(d, config))
+	if err != nil {
+		return fmt.Errorf("Error creating HuaweiCloud VPC client: %s", err)
+	}
+	if err := updateResourceTags(vpcClient, d, config, vpcClient.ServiceURL(vpcClient.ProjectID, "vpcs", d.Id(), "tags")); err != nil {
+		return err
+	}
+
 	return resourceNetworkingRouterV2Read(d, meta)
 }
 
 func resourceNetworkingRouterV2Read(d *schema.ResourceData, meta interface{}) error {
-	config := meta.(*Config)
+	config := GetProjectConfig(d, meta.(*Config))
 	networkingClient, err := config.networkingV2Client(GetRegion(d, config))
 	if err != nil {
 		return fmt.Errorf("Error creating HuaweiCloud networking client: %s", err)
@@ -227,6 +242,14 @@ func resourceNetworkingRouterV2Read(d *schema.ResourceData, meta interface{}) er
 		log.Printf("[DEBUG] unable to set external_fixed_ip: %s", err)
 	}
 
+	vpcClient, err := config.vpcV2Client(GetRegion(d, config))
+	if err != nil {
+		return fmt.Errorf("Error creating HuaweiCloud VPC client: %s", err)
+	}
+	if err := readResourceTags(vpcClient, d, config, vpcClient.ServiceURL(vpcClient.ProjectID, "vpcs", d.Id(), "tags")); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -235,7 +258,7 @@ func resourceNetworkingRouterV2Update(d *schema.ResourceData, meta interface{})
 	osMutexKV.Lock(routerId)
 	defer osMutexKV.Unlock(routerId)
 
-	config := meta.(*Config)
+	config := GetProjectConfig(d, meta.(*Config))
 	networkingClient, err := config.networkingV2Client(GetRegion(d, config))
 	if err != nil {
 		return fmt.Errorf("Error creating HuaweiCloud networking client: %s", err)
@@ -300,11 +323,21 @@ func resourceNetworkingRouterV2Update(d *schema.ResourceData, meta interface{})
 		return fmt.Errorf("Error updating HuaweiCloud Neutron Router: %s", err)
 	}
 
+	if d.HasChange("tags") {
+		vpcClient, err := config.vpcV2Client(GetRegion(d, config))
+		if err != nil {
+			return fmt.Errorf("Error creating HuaweiCloud VPC client: %s", err)
+		}
+		if err := updateResourceTags(vpcClient, d, config, vpcClient.ServiceURL(vpcClient.ProjectID, "vpcs", d.Id(), "tags")); err != nil {
+			return err
+		}
+	}
+
 	return resourceNetworkingRouterV2Read(d, meta)
 }
 
 func resourceNetworkingRouterV2Delete(d *schema.ResourceData, meta interface{}) error {
-	config := meta.(*Config)
+	config := GetProjectConfig(d, meta.(*Config))
 	networkingClient, err := config.networkingV2Client(GetRegion(d, config))
 	if err != nil {
 		return fmt.Errorf("Error creating HuaweiCloud networking client: %s", err)
@@ -34,6 +34,12 @@ func resourceNetworkingSecGroupV2() *schema.Resource {
 				Computed: true,
 				ForceNew: true,
 			},
+			"project_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+			"enterprise_project_id": enterpriseProjectIDSchema(),
 			"name": &schema.Schema{
 				Type:     schema.TypeString,
 				Required: true,
@@ -60,7 +66,7 @@ func resourceNetworkingSecGroupV2() *schema.Resource {
 
 func resourceNetworkingSecGroupV2Create(d *schema.ResourceData, meta interface{}) error {
 
-	config := meta.(*Config)
+	config := GetProjectConfig(d, meta.(*Config))
 	networkingClient, err := config.networkingV2Client(GetRegion(d, config))
 	if err != nil {
 		return fmt.Errorf("Error creating HuaweiCloud networking client: %s", err)
@@ -98,19 +104,24 @@ func resourceNetworkingSecGroupV2Create(d *schema.ResourceData, meta interface{}
 
 	d.SetId(security_group.ID)
 
+	if err := migrateEnterpriseProjectOnCreate(d, config, epsResourceTypeSecurityGroup); err != nil {
+		return err
+	}
+
 	return resourceNetworkingSecGroupV2Read(d, meta)
 }
 
 func resourceNetworkingSecGroupV2Read(d *schema.ResourceData, meta interface{}) error {
 	log.Printf("[DEBUG] Retrieve information about security group: %s", d.Id())
 
-	config := meta.(*Config)
+	config := GetProjectConfig(d, meta.(*Config))
 	networkingClient, err := config.networkingV2Client(GetRegion(d, config))
 	if err != nil {
 		return fmt.Errorf("Error creating HuaweiCloud networking client: %s", err)
 	}
 
-	security_group, err := groups.Get(networkingClient, d.Id()).Extract()
+	result := groups.Get(networkingClient, d.Id())
+	security_group, err := result.Extract()
 
 	if err != nil {
 		return CheckDeleted(d, err, "HuaweiCloud Neutron Security group")
@@ -120,12 +131,13 @@ func resourceNetworkingSecGroupV2Read(d *schema.ResourceData, meta interface{})
 	d.Set("tenant_id", security_group.TenantID)
 	d.Set("name", security_group.Name)
 	d.Set("region", GetRegion(d, config))
+	setEnterpriseProjectID(d, result.Body, "security_group")
 
 	return nil
 }
 
 func resourceNetworkingSecGroupV2Update(d *schema.ResourceData, meta interface{}) error {
-	config := meta.(*Config)
+	config := GetProjectConfig(d, meta.(*Config))
 	networkingClient, err := config.networkingV2Client(GetRegion(d, config))
 	if err != nil {
 		return fmt.Errorf("Error creating HuaweiCloud networking client: %s", err)
@@ -152,13 +164,19 @@ func resourceNetworkingSecGroupV2Update(d *schema.ResourceData, meta interface{}
 		}
 	}
 
+	if d.HasChange("enterprise_project_id") {
+		if err := migrateEnterpriseProject(d, config, epsResourceTypeSecurityGroup); err != nil {
+			return err
+		}
+	}
+
 	return resourceNetworkingSecGroupV2Read(d, meta)
 }
 
 func resourceNetworkingSecGroupV2Delete(d *schema.ResourceData, meta interface{}) error {
 	log.Printf("[DEBUG] Destroy security group: %s", d.Id())
 
-	config := meta.(*Config)
+	config := GetProjectConfig(d, meta.(*Config))
 	networkingClient, err := config.networkingV2Client(GetRegion(d, config))
 	if err != nil {
 		return fmt.Errorf("Error creating HuaweiCloud networking client: %s", err)
@@ -169,7 +187,7 @@ func resourceNetworkingSecGroupV2Delete(d *schema.ResourceData, meta interface{}
 		Target:     []string{"DELETED"},
 		Refresh:    waitForSecGroupDelete(networkingClient, d.Id()),
 		Timeout:    d.Timeout(schema.TimeoutDelete),
-		Delay:      5 * time.Second,
+		Delay:      config.stateRefreshDelay(5 * time.Second),
 		MinTimeout: 3 * time.Second,
 	}
 
@@ -34,6 +34,10 @@ func resourceRdsInstance() *schema.Resource {
 				Computed: true,
 				ForceNew: true,
 			},
+			"enterprise_project_id": enterpriseProjectIDSchema(),
+			"tags":                  tagsSchema(),
+			"tags_all":              tagsAllSchema(),
+			"deletion_protection":   deletionProtectionSchema(),
 
 			"name": &schema.Schema{
 				Type:     schema.TypeString,
@@ -355,6 +359,18 @@ func resourceInstanceCreate(d *schema.ResourceData, meta interface{}) error {
 			instance.ID, err)
 	}
 
+	if err := migrateEnterpriseProjectOnCreate(d, config, epsResourceTypeRDS); err != nil {
+		return err
+	}
+
+	rdsV3Client, err := config.rdsV3Client(GetRegion(d, config))
+	if err != nil {
+		return fmt.Errorf("Error creating HuaweiCloud RDS v3 client: %s", err)
+	}
+	if err := updateResourceTags(rdsV3Client, d, config, rdsV3Client.ServiceURL("instances", d.Id(), "tags")); err != nil {
+		return err
+	}
+
 	if instance.ID != "" {
 		return resourceInstanceRead(d, meta)
 	}
@@ -369,7 +385,8 @@ func resourceInstanceRead(d *schema.ResourceData, meta interface{}) error {
 	}
 
 	instanceID := d.Id()
-	instance, err := instances.Get(client, instanceID).Extract()
+	result := instances.Get(client, instanceID)
+	instance, err := result.Extract()
 	if err != nil {
 		return CheckDeleted(d, err, "instance")
 	}
@@ -390,6 +407,7 @@ func resourceInstanceRead(d *schema.ResourceData, meta interface{}) error {
 	d.Set("availabilityzone", instance.AvailabilityZone)
 	d.Set("vpc", instance.Vpc)
 	d.Set("status", instance.Status)
+	setEnterpriseProjectID(d, result.Body, "instance")
 
 	nicsList := make([]map[string]interface{}, 0, 1)
 	nics := map[string]interface{}{
@@ -450,10 +468,23 @@ func resourceInstanceRead(d *schema.ResourceData, meta interface{}) error {
 
 	d.Set("updated", instance.Updated)
 	d.Set("created", instance.Created)
+
+	rdsV3Client, err := config.rdsV3Client(GetRegion(d, config))
+	if err != nil {
+		return fmt.Errorf("Error creating HuaweiCloud RDS v3 client: %s", err)
+	}
+	if err := readResourceTags(rdsV3Client, d, config, rdsV3Client.ServiceURL("instances", d.Id(), "tags")); err != nil {
+		return err
+	}
+
 	return nil
 }
 
 func resourceInstanceDelete(d *schema.ResourceData, meta interface{}) error {
+	if err := checkDeletionProtection(d, "RDS instance"); err != nil {
+		return err
+	}
+
 	config := meta.(*Config)
 	client, err := config.RdsV1Client(GetRegion(d, config))
 	if err != nil {
@@ -612,6 +643,22 @@ func resourceInstanceUpdate(d *schema.ResourceData, meta interface{}) error {
 		log.Printf("[DEBUG] Successfully updated instance %s policy: %+v", id, updatepolicyOpts)
 	}
 
+	if d.HasChange("enterprise_project_id") {
+		if err := migrateEnterpriseProject(d, config, epsResourceTypeRDS); err != nil {
+			return err
+		}
+	}
+
+	if d.HasChange("tags") {
+		rdsV3Client, err := config.rdsV3Client(GetRegion(d, config))
+		if err != nil {
+			return fmt.Errorf("Error creating HuaweiCloud RDS v3 client: %s", err)
+		}
+		if err := updateResourceTags(rdsV3Client, d, config, rdsV3Client.ServiceURL("instances", d.Id(), "tags")); err != nil {
+			return err
+		}
+	}
+
 	log.Printf("[DEBUG] Successfully updated instance %s", id)
 	d.SetId(id)
 	return resourceInstanceRead(d, meta)
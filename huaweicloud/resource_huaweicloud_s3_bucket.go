@@ -149,6 +149,7 @@ func resourceS3Bucket() *schema.Resource {
 				Optional: true,
 				Computed: true,
 			},
+			"enterprise_project_id": enterpriseProjectIDSchema(),
 			"website_endpoint": {
 				Type:     schema.TypeString,
 				Optional: true,
@@ -275,6 +276,8 @@ func resourceS3Bucket() *schema.Resource {
 				Default:  false,
 			},
 
+			"deletion_protection": deletionProtectionSchema(),
+
 			"tags": tagsSchema(),
 		},
 	}
@@ -320,7 +323,11 @@ func resourceS3BucketCreate(d *schema.ResourceData, meta interface{}) error {
 
 	err = resource.Retry(5*time.Minute, func() *resource.RetryError {
 		log.Printf("[DEBUG] Trying to create new S3 bucket: %q", bucket)
-		ret, err := s3conn.CreateBucket(req)
+		createReq, ret := s3conn.CreateBucketRequest(req)
+		if v, ok := d.GetOk("enterprise_project_id"); ok {
+			createReq.HTTPRequest.Header.Set("x-obs-epid", v.(string))
+		}
+		err := createReq.Send()
 		log.Printf("[DEBUG] Created new S3 bucket: %+v.\n", ret)
 		if awsErr, ok := err.(awserr.Error); ok {
 			if awsErr.Code() == "OperationAborted" {
@@ -399,6 +406,13 @@ func resourceS3BucketUpdate(d *schema.ResourceData, meta interface{}) error {
 		}
 	}
 
+	// A new bucket was created in its enterprise project already.
+	if d.HasChange("enterprise_project_id") && !d.IsNewResource() {
+		if err := migrateEnterpriseProject(d, config, epsResourceTypeBucket); err != nil {
+			return err
+		}
+	}
+
 	return resourceS3BucketRead(d, meta)
 }
 
@@ -409,10 +423,16 @@ func resourceS3BucketRead(d *schema.ResourceData, meta interface{}) error {
 		return fmt.Errorf("Error creating HuaweiCloud s3 client: %s", err)
 	}
 
+	var enterpriseProjectID string
 	_, err = retryOnAwsCode("NoSuchBucket", func() (interface{}, error) {
-		return s3conn.HeadBucket(&s3.HeadBucketInput{
+		req, resp := s3conn.HeadBucketRequest(&s3.HeadBucketInput{
 			Bucket: aws.String(d.Id()),
 		})
+		err := req.Send()
+		if err == nil {
+			enterpriseProjectID = req.HTTPResponse.Header.Get("x-obs-epid")
+		}
+		return resp, err
 	})
 	if err != nil {
 		if awsError, ok := err.(awserr.RequestFailure); ok && awsError.StatusCode() == 404 {
@@ -432,6 +452,9 @@ func resourceS3BucketRead(d *schema.ResourceData, meta interface{}) error {
 	}
 
 	d.Set("bucket_domain_name", bucketDomainName(d.Get("bucket").(string)))
+	if enterpriseProjectID != "" {
+		d.Set("enterprise_project_id", enterpriseProjectID)
+	}
 
 	// Read the policy
 	if _, ok := d.GetOk("policy"); ok {
@@ -796,6 +819,10 @@ func resourceS3BucketRead(d *schema.ResourceData, meta interface{}) error {
 }
 
 func resourceS3BucketDelete(d *schema.ResourceData, meta interface{}) error {
+	if err := checkDeletionProtection(d, "bucket"); err != nil {
+		return err
+	}
+
 	config := meta.(*Config)
 	s3conn, err := config.computeS3conn(GetRegion(d, config))
 	if err != nil {
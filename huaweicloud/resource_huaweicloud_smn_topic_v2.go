@@ -3,8 +3,10 @@ package huaweicloud
 import (
 	"fmt"
 	"log"
+	"strings"
 
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/huaweicloud/golangsdk"
 
 	"github.com/huaweicloud/golangsdk/openstack/smn/v2/topics"
 )
@@ -52,6 +54,8 @@ func resourceTopic() *schema.Resource {
 				ForceNew: true,
 				Computed: true,
 			},
+			"tags":     tagsSchema(),
+			"tags_all": tagsAllSchema(),
 		},
 	}
 }
@@ -76,6 +80,9 @@ func resourceTopicCreate(d *schema.ResourceData, meta interface{}) error {
 	log.Printf("[DEBUG] Create : topic.TopicUrn %s", topic.TopicUrn)
 	if topic.TopicUrn != "" {
 		d.SetId(topic.TopicUrn)
+		if err := updateResourceTags(client, d, config, resourceTopicTagsURL(client, d)); err != nil {
+			return err
+		}
 		return resourceTopicRead(d, meta)
 	}
 
@@ -104,6 +111,10 @@ func resourceTopicRead(d *schema.ResourceData, meta interface{}) error {
 	d.Set("update_time", topicGet.UpdateTime)
 	d.Set("create_time", topicGet.CreateTime)
 
+	if err := readResourceTags(client, d, config, resourceTopicTagsURL(client, d)); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -136,11 +147,20 @@ func resourceTopicUpdate(d *schema.ResourceData, meta interface{}) error {
 	log.Printf("[DEBUG] Updating topic %s", d.Id())
 	id := d.Id()
 
-	var updateOpts topics.UpdateOps
-	if d.HasChange("display_name") {
-		updateOpts.DisplayName = d.Get("display_name").(string)
+	if d.HasChange("tags") {
+		if err := updateResourceTags(client, d, config, resourceTopicTagsURL(client, d)); err != nil {
+			return err
+		}
 	}
 
+	// The tags are the only other argument which can be updated.
+	if !d.HasChange("display_name") {
+		return resourceTopicRead(d, meta)
+	}
+
+	var updateOpts topics.UpdateOps
+	updateOpts.DisplayName = d.Get("display_name").(string)
+
 	topic, err := topics.Update(client, updateOpts, id).Extract()
 	if err != nil {
 		return fmt.Errorf("Error updating topic from result: %s", err)
@@ -153,3 +173,9 @@ func resourceTopicUpdate(d *schema.ResourceData, meta interface{}) error {
 	}
 	return nil
 }
+
+// resourceTopicTagsURL returns the URL of the tags of the topic. They are
+// served next to the notifications API rather than under it.
+func resourceTopicTagsURL(client *golangsdk.ServiceClient, d *schema.ResourceData) string {
+	return client.Endpoint + strings.Join([]string{tagsResourceTypeSMNTopic, d.Id(), "tags"}, "/")
+}
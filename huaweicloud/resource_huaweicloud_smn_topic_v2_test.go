@@ -2,8 +2,10 @@ package huaweicloud
 
 import (
 	"fmt"
+	"reflect"
 	"testing"
 
+	"github.com/hashicorp/terraform/config"
 	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/terraform"
 	"github.com/huaweicloud/golangsdk/openstack/smn/v2/topics"
@@ -109,3 +111,123 @@ resource "huaweicloud_smn_topic_v2" "topic_1" {
   display_name    = "The update display name of topic_1"
 }
 `
+
+func TestFakeCloudResourceTags(t *testing.T) {
+	t.Parallel()
+
+	srv, config := testFakeCloud(t)
+	defer srv.Close()
+
+	r := resourceTopic()
+	raw := map[string]interface{}{
+		"name": "topic_1",
+		"tags": map[string]interface{}{"foo": "bar", "key": "value"},
+	}
+	state := testFakeCloudApply(t, r, nil, raw, config)
+	if tags := srv.Tags("smn/topics", state.ID); !reflect.DeepEqual(tags, raw["tags"]) {
+		t.Fatalf("Unexpected tags of the topic: %v", tags)
+	}
+
+	// System tags are neither read nor removed.
+	srv.SetTag("smn/topics", state.ID, "_sys_enterprise_project_id", "0")
+
+	raw["tags"] = map[string]interface{}{"foo": "baz", "new": "tag"}
+	state = testFakeCloudApply(t, r, state, raw, config)
+	expected := map[string]interface{}{"foo": "baz", "new": "tag", "_sys_enterprise_project_id": "0"}
+	if tags := srv.Tags("smn/topics", state.ID); !reflect.DeepEqual(tags, expected) {
+		t.Fatalf("Unexpected tags of the topic: %v", tags)
+	}
+	if v := state.Attributes["tags.%"]; v != "2" {
+		t.Fatalf("Expected 2 tags in the state, got %s", v)
+	}
+
+	// A tag added outside of Terraform is read back.
+	srv.SetTag("smn/topics", state.ID, "other", "value")
+	state, err := r.Refresh(state, config)
+	if err != nil {
+		t.Fatalf("Error reading topic: %s", err)
+	}
+	if v := state.Attributes["tags.other"]; v != "value" {
+		t.Fatalf("Expected the tag change to be read, got %s", v)
+	}
+
+	testFakeCloudDestroy(t, r, state, config)
+}
+
+func TestFakeCloudDefaultTags(t *testing.T) {
+	t.Parallel()
+
+	srv, providerConfig := testFakeCloud(t)
+	defer srv.Close()
+	providerConfig.DefaultTags = map[string]string{"owner": "team-a", "cost-center": "cc-1"}
+
+	// The tags of the resource take precedence over the default tags.
+	r := resourceTopic()
+	raw := map[string]interface{}{
+		"name": "topic_1",
+		"tags": map[string]interface{}{"owner": "team-b", "foo": "bar"},
+	}
+	state := testFakeCloudApply(t, r, nil, raw, providerConfig)
+	expected := map[string]interface{}{"owner": "team-b", "cost-center": "cc-1", "foo": "bar"}
+	if tags := srv.Tags("smn/topics", state.ID); !reflect.DeepEqual(tags, expected) {
+		t.Fatalf("Unexpected tags of the topic: %v", tags)
+	}
+	if v := state.Attributes["tags_all.%"]; v != "3" {
+		t.Fatalf("Expected 3 tags in tags_all, got %s", v)
+	}
+	if v := state.Attributes["tags.%"]; v != "2" {
+		t.Fatalf("Expected 2 tags in tags, got %s", v)
+	}
+
+	c, err := config.NewRawConfig(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff, err := r.Diff(state, terraform.NewResourceConfig(c)); err != nil || diff != nil {
+		t.Fatalf("Expected no changes, got %v, %v", diff, err)
+	}
+
+	// A new default tag can't show up in the plan, the resource is left as is.
+	providerConfig.DefaultTags = map[string]string{"owner": "team-a", "cost-center": "cc-1", "env": "prod"}
+	state, err = r.Refresh(state, providerConfig)
+	if err != nil {
+		t.Fatalf("Error reading topic: %s", err)
+	}
+	if diff, err := r.Diff(state, terraform.NewResourceConfig(c)); err != nil || diff != nil {
+		t.Fatalf("Expected no changes, got %v, %v", diff, err)
+	}
+
+	// It is added with the next change of the tags of the resource, along with
+	// the removal of a default tag.
+	providerConfig.DefaultTags = map[string]string{"owner": "team-a", "env": "prod"}
+	raw["tags"] = map[string]interface{}{"owner": "team-b", "foo": "baz"}
+	c, err = config.NewRawConfig(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state, err = r.Refresh(state, providerConfig)
+	if err != nil {
+		t.Fatalf("Error reading topic: %s", err)
+	}
+	state = testFakeCloudApply(t, r, state, raw, providerConfig)
+	expected = map[string]interface{}{"owner": "team-b", "env": "prod", "foo": "baz"}
+	if tags := srv.Tags("smn/topics", state.ID); !reflect.DeepEqual(tags, expected) {
+		t.Fatalf("Unexpected tags of the topic: %v", tags)
+	}
+	if diff, err := r.Diff(state, terraform.NewResourceConfig(c)); err != nil || diff != nil {
+		t.Fatalf("Expected no changes, got %v, %v", diff, err)
+	}
+
+	// A default tag changed on the cloud is brought back to its value.
+	srv.SetTag("smn/topics", state.ID, "env", "dev")
+	state, err = r.Refresh(state, providerConfig)
+	if err != nil {
+		t.Fatalf("Error reading topic: %s", err)
+	}
+	state = testFakeCloudApply(t, r, state, raw, providerConfig)
+	if tags := srv.Tags("smn/topics", state.ID); !reflect.DeepEqual(tags, expected) {
+		t.Fatalf("Unexpected tags of the topic: %v", tags)
+	}
+
+	testFakeCloudDestroy(t, r, state, providerConfig)
+}
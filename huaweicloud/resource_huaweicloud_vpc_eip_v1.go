@@ -35,6 +35,14 @@ func resourceVpcEIPV1() *schema.Resource {
 				Computed: true,
 				ForceNew: true,
 			},
+			"project_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+			"enterprise_project_id": enterpriseProjectIDSchema(),
+			"tags":                  tagsSchema(),
+			"tags_all":              tagsAllSchema(),
 			"publicip": &schema.Schema{
 				Type:     schema.TypeList,
 				Required: true,
@@ -101,7 +109,7 @@ func resourceVpcEIPV1() *schema.Resource {
 }
 
 func resourceVpcEIPV1Create(d *schema.ResourceData, meta interface{}) error {
-	config := meta.(*Config)
+	config := GetProjectConfig(d, meta.(*Config))
 	networkingClient, err := config.networkingV1Client(GetRegion(d, config))
 	if err != nil {
 		return fmt.Errorf("Error creating networking client: %s", err)
@@ -113,6 +121,7 @@ func resourceVpcEIPV1Create(d *schema.ResourceData, meta interface{}) error {
 			Bandwidth: resourceBandWidth(d),
 		},
 		MapValueSpecs(d),
+		d.Get("enterprise_project_id").(string),
 	}
 
 	log.Printf("[DEBUG] Create Options: %#v", createOpts)
@@ -124,31 +133,40 @@ func resourceVpcEIPV1Create(d *schema.ResourceData, meta interface{}) error {
 	log.Printf("[DEBUG] Waiting for EIP %#v to become available.", eIP)
 
 	timeout := d.Timeout(schema.TimeoutCreate)
-	err = waitForEIPActive(networkingClient, eIP.ID, timeout)
+	err = waitForEIPActive(config, networkingClient, eIP.ID, timeout)
 	if err != nil {
 		return fmt.Errorf(
 			"Error waiting for EIP (%s) to become ready: %s",
 			eIP.ID, err)
 	}
 
-	err = bindToPort(d, eIP.ID, networkingClient, timeout)
+	err = bindToPort(d, config, eIP.ID, networkingClient, timeout)
 	if err != nil {
 		return fmt.Errorf("Error binding eip:%s to port: %s", eIP.ID, err)
 	}
 
 	d.SetId(eIP.ID)
 
+	vpcClient, err := config.vpcV2Client(GetRegion(d, config))
+	if err != nil {
+		return fmt.Errorf("Error creating HuaweiCloud VPC client: %s", err)
+	}
+	if err := updateResourceTags(vpcClient, d, config, vpcClient.ServiceURL(vpcClient.ProjectID, "publicips", d.Id(), "tags")); err != nil {
+		return err
+	}
+
 	return resourceVpcEIPV1Read(d, meta)
 }
 
 func resourceVpcEIPV1Read(d *schema.ResourceData, meta interface{}) error {
-	config := meta.(*Config)
+	config := GetProjectConfig(d, meta.(*Config))
 	networkingClient, err := config.networkingV1Client(GetRegion(d, config))
 	if err != nil {
 		return fmt.Errorf("Error creating networking client: %s", err)
 	}
 
-	eIP, err := eips.Get(networkingClient, d.Id()).Extract()
+	result := eips.Get(networkingClient, d.Id())
+	eIP, err := result.Extract()
 	if err != nil {
 		return CheckDeleted(d, err, "eIP")
 	}
@@ -178,12 +196,21 @@ func resourceVpcEIPV1Read(d *schema.ResourceData, meta interface{}) error {
 	}
 	d.Set("bandwidth", bW)
 	d.Set("region", GetRegion(d, config))
+	setEnterpriseProjectID(d, result.Body, "publicip")
+
+	vpcClient, err := config.vpcV2Client(GetRegion(d, config))
+	if err != nil {
+		return fmt.Errorf("Error creating HuaweiCloud VPC client: %s", err)
+	}
+	if err := readResourceTags(vpcClient, d, config, vpcClient.ServiceURL(vpcClient.ProjectID, "publicips", d.Id(), "tags")); err != nil {
+		return err
+	}
 
 	return nil
 }
 
 func resourceVpcEIPV1Update(d *schema.ResourceData, meta interface{}) error {
-	config := meta.(*Config)
+	config := GetProjectConfig(d, meta.(*Config))
 	networkingClient, err := config.networkingV1Client(GetRegion(d, config))
 	if err != nil {
 		return fmt.Errorf("Error creating networking client: %s", err)
@@ -227,18 +254,34 @@ func resourceVpcEIPV1Update(d *schema.ResourceData, meta interface{}) error {
 
 	}
 
+	if d.HasChange("enterprise_project_id") {
+		if err := migrateEnterpriseProject(d, config, epsResourceTypeEIP); err != nil {
+			return err
+		}
+	}
+
+	if d.HasChange("tags") {
+		vpcClient, err := config.vpcV2Client(GetRegion(d, config))
+		if err != nil {
+			return fmt.Errorf("Error creating HuaweiCloud VPC client: %s", err)
+		}
+		if err := updateResourceTags(vpcClient, d, config, vpcClient.ServiceURL(vpcClient.ProjectID, "publicips", d.Id(), "tags")); err != nil {
+			return err
+		}
+	}
+
 	return resourceVpcEIPV1Read(d, meta)
 }
 
 func resourceVpcEIPV1Delete(d *schema.ResourceData, meta interface{}) error {
-	config := meta.(*Config)
+	config := GetProjectConfig(d, meta.(*Config))
 	networkingClient, err := config.networkingV1Client(GetRegion(d, config))
 	if err != nil {
 		return fmt.Errorf("Error creating VPC client: %s", err)
 	}
 
 	timeout := d.Timeout(schema.TimeoutDelete)
-	err = unbindToPort(d, d.Id(), networkingClient, timeout)
+	err = unbindToPort(d, config, d.Id(), networkingClient, timeout)
 	if err != nil {
 		return fmt.Errorf("Error unbinding eip:%s to port: %s", d.Id(), err)
 	}
@@ -248,7 +291,7 @@ func resourceVpcEIPV1Delete(d *schema.ResourceData, meta interface{}) error {
 		Target:     []string{"DELETED"},
 		Refresh:    waitForEIPDelete(networkingClient, d.Id()),
 		Timeout:    timeout,
-		Delay:      5 * time.Second,
+		Delay:      config.stateRefreshDelay(5 * time.Second),
 		MinTimeout: 3 * time.Second,
 	}
 
@@ -329,7 +372,7 @@ func resourceBandWidth(d *schema.ResourceData) eips.BandwidthOpts {
 	return bandwidth
 }
 
-func bindToPort(d *schema.ResourceData, eipID string, networkingClient *golangsdk.ServiceClient, timeout time.Duration) error {
+func bindToPort(d *schema.ResourceData, config *Config, eipID string, networkingClient *golangsdk.ServiceClient, timeout time.Duration) error {
 	publicIPRaw := d.Get("publicip").([]interface{})
 	rawMap := publicIPRaw[0].(map[string]interface{})
 	port_id, ok := rawMap["port_id"]
@@ -345,10 +388,10 @@ func bindToPort(d *schema.ResourceData, eipID string, networkingClient *golangsd
 	if err != nil {
 		return err
 	}
-	return waitForEIPActive(networkingClient, eipID, timeout)
+	return waitForEIPActive(config, networkingClient, eipID, timeout)
 }
 
-func unbindToPort(d *schema.ResourceData, eipID string, networkingClient *golangsdk.ServiceClient, timeout time.Duration) error {
+func unbindToPort(d *schema.ResourceData, config *Config, eipID string, networkingClient *golangsdk.ServiceClient, timeout time.Duration) error {
 	publicIPRaw := d.Get("publicip").([]interface{})
 	rawMap := publicIPRaw[0].(map[string]interface{})
 	port_id, ok := rawMap["port_id"]
@@ -364,15 +407,15 @@ func unbindToPort(d *schema.ResourceData, eipID string, networkingClient *golang
 	if err != nil {
 		return err
 	}
-	return waitForEIPActive(networkingClient, eipID, timeout)
+	return waitForEIPActive(config, networkingClient, eipID, timeout)
 }
 
-func waitForEIPActive(networkingClient *golangsdk.ServiceClient, eipID string, timeout time.Duration) error {
+func waitForEIPActive(config *Config, networkingClient *golangsdk.ServiceClient, eipID string, timeout time.Duration) error {
 	stateConf := &resource.StateChangeConf{
 		Target:     []string{"ACTIVE"},
 		Refresh:    getEIPStatus(networkingClient, eipID),
 		Timeout:    timeout,
-		Delay:      5 * time.Second,
+		Delay:      config.stateRefreshDelay(5 * time.Second),
 		MinTimeout: 3 * time.Second,
 	}
 
@@ -130,3 +130,93 @@ resource "huaweicloud_vpc_eip_v1" "eip_1" {
   }
 }
 `
+
+func TestFakeCloudVpcEIPV1Lifecycle(t *testing.T) {
+	t.Parallel()
+
+	srv, config := testFakeCloud(t)
+	defer srv.Close()
+
+	r := resourceVpcEIPV1()
+	raw := map[string]interface{}{
+		"publicip": []interface{}{
+			map[string]interface{}{"type": "5_bgp"},
+		},
+		"bandwidth": []interface{}{
+			map[string]interface{}{"name": "bandwidth_1", "size": 5, "share_type": "PER"},
+		},
+	}
+
+	state := testFakeCloudApply(t, r, nil, raw, config)
+	if state.Attributes["publicip.0.ip_address"] == "" {
+		t.Fatal("Expected the address of the EIP to be read")
+	}
+
+	raw["bandwidth"] = []interface{}{
+		map[string]interface{}{"name": "bandwidth_2", "size": 8, "share_type": "PER"},
+	}
+	state = testFakeCloudApply(t, r, state, raw, config)
+	publicIP, _ := srv.Get("vpc/publicips", state.ID)
+	bandwidth, _ := srv.Get("vpc/bandwidths", publicIP["bandwidth_id"].(string))
+	if bandwidth["name"] != "bandwidth_2" || bandwidth["size"] != float64(8) {
+		t.Fatalf("Bandwidth not updated: %v", bandwidth)
+	}
+
+	testFakeCloudDestroy(t, r, state, config)
+	if _, ok := srv.Get("vpc/publicips", state.ID); ok {
+		t.Fatalf("EIP %s still exists", state.ID)
+	}
+}
+
+func TestFakeCloudEnterpriseProjectID(t *testing.T) {
+	t.Parallel()
+
+	srv, config := testFakeCloud(t)
+	defer srv.Close()
+
+	// The EIP API takes the enterprise project on create.
+	r := resourceVpcEIPV1()
+	raw := map[string]interface{}{
+		"publicip": []interface{}{
+			map[string]interface{}{"type": "5_bgp"},
+		},
+		"bandwidth": []interface{}{
+			map[string]interface{}{"name": "bandwidth_1", "size": 5, "share_type": "PER"},
+		},
+		"enterprise_project_id": "ep-1",
+	}
+	state := testFakeCloudApply(t, r, nil, raw, config)
+	if publicIP, _ := srv.Get("vpc/publicips", state.ID); publicIP["enterprise_project_id"] != "ep-1" {
+		t.Fatalf("Expected the EIP in enterprise project ep-1, got %v", publicIP["enterprise_project_id"])
+	}
+
+	raw["enterprise_project_id"] = "ep-2"
+	state = testFakeCloudApply(t, r, state, raw, config)
+	if publicIP, _ := srv.Get("vpc/publicips", state.ID); publicIP["enterprise_project_id"] != "ep-2" {
+		t.Fatalf("Expected the EIP migrated to enterprise project ep-2, got %v", publicIP["enterprise_project_id"])
+	}
+
+	// A migration made outside of Terraform is read back.
+	srv.Update("vpc/publicips", state.ID, map[string]interface{}{"enterprise_project_id": "ep-3"})
+	state, err := r.Refresh(state, config)
+	if err != nil {
+		t.Fatalf("Error reading EIP: %s", err)
+	}
+	if v := state.Attributes["enterprise_project_id"]; v != "ep-3" {
+		t.Fatalf("Expected the enterprise project change to be read, got %s", v)
+	}
+
+	// Neutron doesn't take an enterprise project, the security group is
+	// migrated once created.
+	r = resourceNetworkingSecGroupV2()
+	state = testFakeCloudApply(t, r, nil, map[string]interface{}{
+		"name":                  "secgroup_1",
+		"enterprise_project_id": "ep-1",
+	}, config)
+	if group, _ := srv.Get("network/security-groups", state.ID); group["enterprise_project_id"] != "ep-1" {
+		t.Fatalf("Expected the security group in enterprise project ep-1, got %v", group["enterprise_project_id"])
+	}
+	if v := state.Attributes["enterprise_project_id"]; v != "ep-1" {
+		t.Fatalf("Unexpected enterprise_project_id: %s", v)
+	}
+}
@@ -1,12 +1,251 @@
 package huaweicloud
 
-import "github.com/hashicorp/terraform/helper/schema"
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/huaweicloud/golangsdk"
+)
 
 // tagsSchema returns the schema to use for tags.
-//
 func tagsSchema() *schema.Schema {
 	return &schema.Schema{
 		Type:     schema.TypeMap,
 		Optional: true,
 	}
 }
+
+// The resource types of the batch tag APIs of the services.
+const (
+	tagsResourceTypeDNSPrivateZone = "DNS-private_zone"
+	tagsResourceTypeDNSPublicZone  = "DNS-public_zone"
+	tagsResourceTypeSMNTopic       = "smn_topic"
+)
+
+// resourceTag is a tag of the batch tag APIs of the services.
+type resourceTag struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// tagsAllSchema returns the schema of the tags of a resource merged with the
+// default tags of the provider.
+func tagsAllSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeMap,
+		Computed: true,
+	}
+}
+
+// updateResourceTags is a helper to update the tags of a resource with the
+// batch tag API of its service, whose tags are served at url, e.g.
+// https://ecs.region.example.com/v1/<project>/cloudservers/<id>/tags. It
+// expects the tags fields to be named "tags" and "tags_all", and applies the
+// default tags of the provider along with the tags of the resource.
+func updateResourceTags(client *golangsdk.ServiceClient, d *schema.ResourceData, config *Config, url string) error {
+	oraw, nraw := d.GetChange("tags")
+	oldTags := d.Get("tags_all").(map[string]interface{})
+	if len(oldTags) == 0 {
+		// The resource was read before tags_all was added.
+		oldTags = oraw.(map[string]interface{})
+	}
+	newTags := mergeDefaultTags(config.DefaultTags, nraw.(map[string]interface{}))
+	create, remove := diffTags(oldTags, newTags)
+
+	if len(remove) > 0 {
+		log.Printf("[DEBUG] Removing tags of %s: %#v", d.Id(), remove)
+		if err := tagsAction(client, url, "delete", remove); err != nil {
+			return fmt.Errorf("Error removing tags of %s: %s", d.Id(), err)
+		}
+	}
+	if len(create) > 0 {
+		log.Printf("[DEBUG] Creating tags of %s: %#v", d.Id(), create)
+		if err := tagsAction(client, url, "create", create); err != nil {
+			return fmt.Errorf("Error creating tags of %s: %s", d.Id(), err)
+		}
+	}
+
+	return nil
+}
+
+// readResourceTags sets the tags and tags_all fields from the tags served at
+// url, leaving out the system tags. The tags are only read if the resource
+// has tags, in its configuration or its state, or the provider has default
+// tags, so that resources which don't use tags don't depend on the tag API of
+// their service. A tag API which isn't found or implemented means no tags.
+func readResourceTags(client *golangsdk.ServiceClient, d *schema.ResourceData, config *Config, url string) error {
+	var tags []resourceTag
+	if len(config.DefaultTags) > 0 || len(d.Get("tags").(map[string]interface{})) > 0 ||
+		len(d.Get("tags_all").(map[string]interface{})) > 0 {
+		var err error
+		tags, err = getResourceTags(client, url)
+		if err != nil {
+			if !tagsUnsupported(err) {
+				return fmt.Errorf("Error retrieving tags of %s: %s", d.Id(), err)
+			}
+			log.Printf("[DEBUG] Tags of %s aren't supported: %s", d.Id(), err)
+		}
+	}
+
+	tagsAll := tagsToMap(tags)
+	if err := d.Set("tags", resourceTagsWithoutDefaults(d, config.DefaultTags, tagsAll)); err != nil {
+		return fmt.Errorf("Error setting tags of %s: %s", d.Id(), err)
+	}
+	if err := d.Set("tags_all", tagsAll); err != nil {
+		return fmt.Errorf("Error setting tags_all of %s: %s", d.Id(), err)
+	}
+
+	return nil
+}
+
+// mergeDefaultTags returns the default tags of the provider merged with the
+// tags of a resource, which take precedence.
+func mergeDefaultTags(defaultTags map[string]string, tags map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{})
+	for k, v := range defaultTags {
+		result[k] = v
+	}
+	for k, v := range tags {
+		result[k] = v
+	}
+
+	return result
+}
+
+// resourceTagsWithoutDefaults returns the tags of a resource out of all its
+// tags, i.e. without the default tags of the provider which the resource
+// doesn't set itself.
+//
+// The default tags can't be merged in the plan with this version of the SDK.
+// A default tag whose value differs is kept, so that the plan brings it back
+// to its default value, but a default tag which is missing can't show up in
+// the plan: it is only added when the tags of the resource change.
+func resourceTagsWithoutDefaults(d *schema.ResourceData, defaultTags, tagsAll map[string]string) map[string]string {
+	own := d.Get("tags").(map[string]interface{})
+
+	result := make(map[string]string)
+	for k, v := range tagsAll {
+		if dv, ok := defaultTags[k]; ok && dv == v {
+			if _, ok := own[k]; !ok {
+				continue
+			}
+		}
+		result[k] = v
+	}
+
+	return result
+}
+
+func tagsAction(client *golangsdk.ServiceClient, url, action string, tags []resourceTag) error {
+	opts := map[string]interface{}{
+		"action": action,
+		"tags":   tags,
+	}
+	_, err := client.Post(url+"/action", opts, nil, &golangsdk.RequestOpts{
+		OkCodes: []int{200, 204},
+	})
+	return err
+}
+
+// getResourceTags returns the tags served at url. Most services return a list
+// of key and value objects, EVS returns a map of the tags.
+func getResourceTags(client *golangsdk.ServiceClient, url string) ([]resourceTag, error) {
+	var body struct {
+		Tags interface{} `json:"tags"`
+	}
+	_, err := client.Get(url, &body, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var tags []resourceTag
+	switch v := body.Tags.(type) {
+	case []interface{}:
+		for _, raw := range v {
+			t, _ := raw.(map[string]interface{})
+			key, _ := t["key"].(string)
+			value, _ := t["value"].(string)
+			tags = append(tags, resourceTag{Key: key, Value: value})
+		}
+	case map[string]interface{}:
+		for key, raw := range v {
+			value, _ := raw.(string)
+			tags = append(tags, resourceTag{Key: key, Value: value})
+		}
+	}
+
+	return tags, nil
+}
+
+// tagsUnsupported returns true if the tag API of a service answered with a
+// 404 or 501 response code, which some services and regions without tag
+// support do.
+func tagsUnsupported(err error) bool {
+	switch e := err.(type) {
+	case golangsdk.ErrDefault404:
+		return true
+	case golangsdk.ErrUnexpectedResponseCode:
+		return e.Actual == http.StatusNotImplemented
+	}
+	return false
+}
+
+// diffTags takes the old and new tags and returns the tags that must be
+// created, and the tags that must be removed.
+func diffTags(oldTags, newTags map[string]interface{}) ([]resourceTag, []resourceTag) {
+	var create, remove []resourceTag
+	for k, v := range newTags {
+		if old, ok := oldTags[k]; !ok || old != v {
+			create = append(create, resourceTag{Key: k, Value: v.(string)})
+		}
+	}
+	for k, v := range oldTags {
+		if _, ok := newTags[k]; !ok {
+			remove = append(remove, resourceTag{Key: k, Value: v.(string)})
+		}
+	}
+
+	return tagsFromList(create), tagsFromList(remove)
+}
+
+// tagsFromList returns the tags which aren't system tags.
+func tagsFromList(tags []resourceTag) []resourceTag {
+	var result []resourceTag
+	for _, t := range tags {
+		if !tagIgnored(t) {
+			result = append(result, t)
+		}
+	}
+
+	return result
+}
+
+// tagsToMap turns the list of tags into a map, leaving out the system tags.
+func tagsToMap(tags []resourceTag) map[string]string {
+	result := make(map[string]string)
+	for _, t := range tags {
+		if !tagIgnored(t) {
+			result[t.Key] = t.Value
+		}
+	}
+
+	return result
+}
+
+// compare a tag against a list of strings and checks if it should be ignored
+// or not. System tags, like _sys_enterprise_project_id, are managed by
+// HuaweiCloud.
+func tagIgnored(t resourceTag) bool {
+	filter := []string{"^_sys_"}
+	for _, v := range filter {
+		log.Printf("[DEBUG] Matching %v with %v\n", v, t.Key)
+		if r, _ := regexp.MatchString(v, t.Key); r {
+			log.Printf("[DEBUG] Found HuaweiCloud system tag %s (val: %s), ignoring.\n", t.Key, t.Value)
+			return true
+		}
+	}
+	return false
+}
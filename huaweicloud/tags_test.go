@@ -0,0 +1,77 @@
+package huaweicloud
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/huaweicloud/golangsdk"
+)
+
+func TestReadResourceTags(t *testing.T) {
+	var requests int
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		if status == http.StatusOK {
+			fmt.Fprint(w, `{"tags":[{"key":"foo","value":"bar"},{"key":"_sys_enterprise_project_id","value":"0"}]}`)
+		}
+	}))
+	defer server.Close()
+
+	client := &golangsdk.ServiceClient{
+		ProviderClient: &golangsdk.ProviderClient{},
+		Endpoint:       server.URL + "/",
+	}
+	r := resourceNetworkingNetworkV2()
+	config := &Config{}
+
+	// A resource without tags doesn't read them.
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{})
+	d.SetId("network")
+	if err := readResourceTags(client, d, config, client.ServiceURL("tags")); err != nil {
+		t.Fatal(err)
+	}
+	if requests != 0 {
+		t.Fatalf("Expected the tags not to be read, got %d requests", requests)
+	}
+
+	// The default tags of the provider make every resource read its tags.
+	config.DefaultTags = map[string]string{"foo": "bar"}
+	if err := readResourceTags(client, d, config, client.ServiceURL("tags")); err != nil {
+		t.Fatal(err)
+	}
+	if requests != 1 {
+		t.Fatalf("Expected the tags to be read, got %d requests", requests)
+	}
+	if tags := d.Get("tags_all").(map[string]interface{}); len(tags) != 1 || tags["foo"] != "bar" {
+		t.Fatalf("Unexpected tags_all: %v", tags)
+	}
+
+	// A tag API which isn't found or implemented means no tags.
+	config.DefaultTags = nil
+	for _, status = range []int{http.StatusNotFound, http.StatusNotImplemented} {
+		d = schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+			"tags": map[string]interface{}{"foo": "bar"},
+		})
+		d.SetId("network")
+		if err := readResourceTags(client, d, config, client.ServiceURL("tags")); err != nil {
+			t.Fatalf("Expected no error on a %d response, got %s", status, err)
+		}
+		if tags := d.Get("tags").(map[string]interface{}); len(tags) != 0 {
+			t.Fatalf("Expected no tags on a %d response, got %v", status, tags)
+		}
+	}
+
+	status = http.StatusInternalServerError
+	d = schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"tags": map[string]interface{}{"foo": "bar"},
+	})
+	if err := readResourceTags(client, d, config, client.ServiceURL("tags")); err == nil {
+		t.Fatal("Expected an error on a 500 response")
+	}
+}
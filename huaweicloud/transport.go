@@ -0,0 +1,248 @@
+package huaweicloud
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"log"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const (
+	defaultRetryMinBackoff = 1 * time.Second
+	defaultRetryMaxBackoff = 30 * time.Second
+)
+
+// RetryRoundTripper satisfies the http.RoundTripper interface and retries
+// requests which failed with 429 or 5xx response codes, or a reset connection.
+// Requests which aren't idempotent, such as POST, are only retried on 429 and
+// 503, which mean that the request wasn't processed. The delay between
+// attempts grows exponentially with jitter, unless the response carries a
+// Retry-After header. Requests are throttled by the optional RateLimiter,
+// retries included.
+type RetryRoundTripper struct {
+	Rt          http.RoundTripper
+	MaxRetries  int
+	RateLimiter *RateLimiter
+
+	// MinBackoff and MaxBackoff bound the delay between attempts, they default
+	// to defaultRetryMinBackoff and defaultRetryMaxBackoff.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
+// RoundTrip performs a round-trip HTTP request, retrying it as needed.
+func (rrt *RetryRoundTripper) RoundTrip(request *http.Request) (*http.Response, error) {
+	// The body is consumed by every attempt, keep a copy to replay it.
+	var body []byte
+	if request.Body != nil {
+		var err error
+		body, err = ioutil.ReadAll(request.Body)
+		request.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		if rrt.RateLimiter != nil {
+			if err := rrt.RateLimiter.Wait(request); err != nil {
+				return nil, err
+			}
+		}
+
+		// Don't modify the caller's request, http.RoundTripper forbids it.
+		try := new(http.Request)
+		*try = *request
+		if body != nil {
+			try.Body = ioutil.NopCloser(bytes.NewReader(body))
+		}
+
+		response, err := rrt.Rt.RoundTrip(try)
+		if attempt >= rrt.MaxRetries || !retryableResponse(request.Method, response, err) {
+			return response, err
+		}
+
+		delay := rrt.backoff(attempt, response)
+		if response != nil {
+			log.Printf("[DEBUG] Retrying %s %s in %s after response code %d (attempt %d of %d)",
+				request.Method, request.URL, delay, response.StatusCode, attempt+1, rrt.MaxRetries)
+			// Drain the body so that the connection can be reused.
+			io.Copy(ioutil.Discard, response.Body)
+			response.Body.Close()
+		} else {
+			log.Printf("[DEBUG] Retrying %s %s in %s after error: %s (attempt %d of %d)",
+				request.Method, request.URL, delay, err, attempt+1, rrt.MaxRetries)
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-request.Context().Done():
+			timer.Stop()
+			return nil, request.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// backoff returns the delay before the next attempt. Retry-After is honored if
+// present, up to MaxBackoff, otherwise the delay doubles on every attempt, with
+// jitter.
+func (rrt *RetryRoundTripper) backoff(attempt int, response *http.Response) time.Duration {
+	min, max := rrt.MinBackoff, rrt.MaxBackoff
+	if min <= 0 {
+		min = defaultRetryMinBackoff
+	}
+	if max <= 0 {
+		max = defaultRetryMaxBackoff
+	}
+
+	if response != nil {
+		if delay, ok := parseRetryAfter(response.Header.Get("Retry-After")); ok {
+			if delay > max {
+				delay = max
+			}
+			return delay
+		}
+	}
+
+	delay := max
+	if attempt < 30 && min<<uint(attempt) < max {
+		delay = min << uint(attempt)
+	}
+
+	// Use a random delay between half and all of the backoff, so that
+	// concurrent requests don't retry at the same time.
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+// retryableResponse returns true if the request failed with a response code or
+// error worth retrying. A request which isn't idempotent may have been processed
+// when the connection was reset or the response is a 500, 502 or 504, so it is
+// only retried on 429 and 503.
+func retryableResponse(method string, response *http.Response, err error) bool {
+	idempotent := false
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		idempotent = true
+	}
+
+	if err != nil {
+		return idempotent && connectionReset(err)
+	}
+
+	switch response.StatusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return true
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusGatewayTimeout:
+		return idempotent
+	}
+	return false
+}
+
+// connectionReset returns true if a request failed because the connection was
+// reset or closed before the whole response was read.
+func connectionReset(err error) bool {
+	if e, ok := err.(*url.Error); ok {
+		err = e.Err
+	}
+	if err == io.ErrUnexpectedEOF {
+		return true
+	}
+	if e, ok := err.(*net.OpError); ok {
+		err = e.Err
+	}
+	if e, ok := err.(*os.SyscallError); ok {
+		err = e.Err
+	}
+	return err == syscall.ECONNRESET
+}
+
+// parseRetryAfter parses a Retry-After header, given either in seconds or as
+// an HTTP date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		delay := time.Until(date)
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, true
+	}
+
+	return 0, false
+}
+
+// RateLimiter is a token bucket allowing a sustained rate of requests per
+// second, with bursts of up to one second worth of requests.
+type RateLimiter struct {
+	mu       sync.Mutex
+	rate     float64
+	burst    float64
+	tokens   float64
+	lastFill time.Time
+}
+
+// NewRateLimiter returns a RateLimiter allowing the given number of requests
+// per second.
+func NewRateLimiter(perSecond int) *RateLimiter {
+	return &RateLimiter{
+		rate:     float64(perSecond),
+		burst:    float64(perSecond),
+		tokens:   float64(perSecond),
+		lastFill: time.Now(),
+	}
+}
+
+// Wait blocks until a request may be sent, or the context of the request is
+// done.
+func (l *RateLimiter) Wait(request *http.Request) error {
+	delay := l.reserve()
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-request.Context().Done():
+		return request.Context().Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// reserve takes a token from the bucket and returns how long to wait until it
+// is available.
+func (l *RateLimiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.tokens += now.Sub(l.lastFill).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.lastFill = now
+
+	l.tokens--
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.rate * float64(time.Second))
+}
@@ -0,0 +1,157 @@
+package huaweicloud
+
+import (
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestRetryRoundTripper(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		body, _ := ioutil.ReadAll(r.Body)
+		if string(body) != "payload" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		switch calls {
+		case 1:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: &RetryRoundTripper{
+		Rt:         http.DefaultTransport,
+		MaxRetries: 2,
+		MinBackoff: time.Millisecond,
+		MaxBackoff: time.Millisecond,
+	}}
+
+	resp, err := client.Post(server.URL, "text/plain", strings.NewReader("payload"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || calls != 3 {
+		t.Fatalf("Expected 200 after 3 calls, got %d after %d calls", resp.StatusCode, calls)
+	}
+
+	// The last response is returned once the retries are exhausted.
+	calls = 1
+	client.Transport.(*RetryRoundTripper).MaxRetries = 0
+	resp, err = client.Post(server.URL, "text/plain", strings.NewReader("payload"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("Expected 503 without retries, got %d", resp.StatusCode)
+	}
+}
+
+func TestRetryRoundTripperNotIdempotent(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: &RetryRoundTripper{
+		Rt:         http.DefaultTransport,
+		MaxRetries: 2,
+		MinBackoff: time.Millisecond,
+		MaxBackoff: time.Millisecond,
+	}}
+
+	// A POST which may have been processed isn't sent again.
+	resp, err := client.Post(server.URL, "text/plain", strings.NewReader("payload"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	resp.Body.Close()
+	if calls != 1 {
+		t.Fatalf("Expected a POST failing with 500 not to be retried, got %d calls", calls)
+	}
+
+	calls = 0
+	resp, err = client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	resp.Body.Close()
+	if calls != 3 {
+		t.Fatalf("Expected a GET failing with 500 to be retried twice, got %d calls", calls)
+	}
+}
+
+func TestRetryRoundTripperBackoff(t *testing.T) {
+	rrt := &RetryRoundTripper{MaxBackoff: time.Second}
+	response := &http.Response{Header: http.Header{"Retry-After": []string{"3600"}}}
+	if delay := rrt.backoff(0, response); delay != time.Second {
+		t.Fatalf("Expected Retry-After to be capped at 1s, got %s", delay)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	if delay, ok := parseRetryAfter("3"); !ok || delay != 3*time.Second {
+		t.Fatalf("Unexpected delay: %s", delay)
+	}
+
+	date := time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)
+	if delay, ok := parseRetryAfter(date); !ok || delay <= 0 || delay > time.Minute {
+		t.Fatalf("Unexpected delay: %s", delay)
+	}
+
+	if _, ok := parseRetryAfter("soon"); ok {
+		t.Fatal("Expected an invalid Retry-After to be ignored")
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	limiter := NewRateLimiter(10)
+	for i := 0; i < 10; i++ {
+		if delay := limiter.reserve(); delay != 0 {
+			t.Fatalf("Expected a burst of 10 requests, request %d waits %s", i+1, delay)
+		}
+	}
+
+	if delay := limiter.reserve(); delay <= 0 || delay > 100*time.Millisecond {
+		t.Fatalf("Expected the 11th request to wait up to 100ms, got %s", delay)
+	}
+}
+
+func TestConnectionReset(t *testing.T) {
+	reset := &net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)}
+	cases := []struct {
+		err      error
+		expected bool
+	}{
+		{reset, true},
+		{&url.Error{Op: "Get", URL: "https://example.com", Err: reset}, true},
+		{io.ErrUnexpectedEOF, true},
+		{&net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}, false},
+		{io.EOF, false},
+	}
+
+	for _, c := range cases {
+		if actual := connectionReset(c.err); actual != c.expected {
+			t.Fatalf("Expected connectionReset(%v) to be %t", c.err, c.expected)
+		}
+	}
+}
@@ -24,13 +24,15 @@ import (
 	"github.com/huaweicloud/golangsdk/openstack/networking/v2/extensions/fwaas_v2/policies"
 	"github.com/huaweicloud/golangsdk/openstack/networking/v2/extensions/fwaas_v2/routerinsertion"
 	"github.com/huaweicloud/golangsdk/openstack/networking/v2/extensions/fwaas_v2/rules"
+	"github.com/huaweicloud/golangsdk/openstack/networking/v2/extensions/natgateways"
 )
 
 // LogRoundTripper satisfies the http.RoundTripper interface and is used to
 // customize the default http client RoundTripper to allow for logging.
 type LogRoundTripper struct {
-	Rt      http.RoundTripper
-	OsDebug bool
+	Rt       http.RoundTripper
+	OsDebug  bool
+	Redactor *Redactor
 }
 
 // RoundTrip performs a round-trip HTTP request and logs relevant information about it.
@@ -47,7 +49,7 @@ func (lrt *LogRoundTripper) RoundTrip(request *http.Request) (*http.Response, er
 	var err error
 
 	if lrt.OsDebug {
-		log.Printf("[DEBUG] HuaweiCloud Request URL: %s %s", request.Method, request.URL)
+		log.Printf("[DEBUG] HuaweiCloud Request URL: %s %s", request.Method, lrt.redactor().RedactURL(request.URL))
 		log.Printf("[DEBUG] Openstack Request Headers:\n%s", FormatHeaders(request.Header, "\n"))
 
 		if request.Body != nil {
@@ -116,10 +118,19 @@ func (lrt *LogRoundTripper) logResponse(original io.ReadCloser, contentType stri
 	return original, nil
 }
 
+// redactor returns the Redactor masking sensitive values, or one for the
+// built-in sensitive names if none is set.
+func (lrt *LogRoundTripper) redactor() *Redactor {
+	if lrt.Redactor == nil {
+		return newRedactor()
+	}
+	return lrt.Redactor
+}
+
 // formatJSON will try to pretty-format a JSON body.
 // It will also mask known fields which contain sensitive information.
 func (lrt *LogRoundTripper) formatJSON(raw []byte) string {
-	var data map[string]interface{}
+	var data interface{}
 
 	err := json.Unmarshal(raw, &data)
 	if err != nil {
@@ -127,23 +138,17 @@ func (lrt *LogRoundTripper) formatJSON(raw []byte) string {
 		return string(raw)
 	}
 
-	// Mask known password fields
-	if v, ok := data["auth"].(map[string]interface{}); ok {
-		if v, ok := v["identity"].(map[string]interface{}); ok {
-			if v, ok := v["password"].(map[string]interface{}); ok {
-				if v, ok := v["user"].(map[string]interface{}); ok {
-					v["password"] = "***"
-				}
+	// Ignore the catalog
+	if v, ok := data.(map[string]interface{}); ok {
+		if v, ok := v["token"].(map[string]interface{}); ok {
+			if _, ok := v["catalog"]; ok {
+				return ""
 			}
 		}
 	}
 
-	// Ignore the catalog
-	if v, ok := data["token"].(map[string]interface{}); ok {
-		if _, ok := v["catalog"]; ok {
-			return ""
-		}
-	}
+	// Mask known sensitive fields
+	data = lrt.redactor().RedactJSON(data)
 
 	pretty, err := json.MarshalIndent(data, "", "  ")
 	if err != nil {
@@ -356,5 +361,33 @@ func (opts ZoneCreateOpts) ToZoneCreateMap() (map[string]interface{}, error) {
 // EIPCreateOpts represents the attributes used when creating a new eip.
 type EIPCreateOpts struct {
 	eips.ApplyOpts
-	ValueSpecs map[string]string `json:"value_specs,omitempty"`
+	ValueSpecs          map[string]string `json:"value_specs,omitempty"`
+	EnterpriseProjectID string            `json:"enterprise_project_id,omitempty"`
+}
+
+// ToPublicIpApplyMap casts a CreateOpts struct to a map.
+// It overrides eips.ToPublicIpApplyMap to add the EnterpriseProjectID field.
+func (opts EIPCreateOpts) ToPublicIpApplyMap() (map[string]interface{}, error) {
+	b, err := opts.ApplyOpts.ToPublicIpApplyMap()
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.EnterpriseProjectID != "" {
+		b["enterprise_project_id"] = opts.EnterpriseProjectID
+	}
+
+	return b, nil
+}
+
+// NatGatewayCreateOpts represents the attributes used when creating a new nat gateway.
+type NatGatewayCreateOpts struct {
+	natgateways.CreateOpts
+	EnterpriseProjectID string `json:"enterprise_project_id,omitempty"`
+}
+
+// ToNatGatewayCreateMap casts a CreateOpts struct to a map.
+// It overrides natgateways.ToNatGatewayCreateMap to add the EnterpriseProjectID field.
+func (opts NatGatewayCreateOpts) ToNatGatewayCreateMap() (map[string]interface{}, error) {
+	return BuildRequest(opts, "nat_gateway")
 }
@@ -51,6 +51,16 @@ func GetRegion(d *schema.ResourceData, config *Config) string {
 	return config.Region
 }
 
+// GetProjectConfig returns the Config of the project that was specified in the
+// resource. If a project was not set, the provider-level Config is returned.
+func GetProjectConfig(d *schema.ResourceData, config *Config) *Config {
+	if v, ok := d.GetOk("project_id"); ok {
+		return config.projectConfig(v.(string))
+	}
+
+	return config
+}
+
 // AddValueSpecs expands the 'value_specs' object and removes 'value_specs'
 // from the reqeust body.
 func AddValueSpecs(body map[string]interface{}) map[string]interface{} {
@@ -86,7 +96,7 @@ func MapResourceProp(d *schema.ResourceData, prop string) map[string]interface{}
 var REDACT_HEADERS = []string{"x-auth-token", "x-auth-key", "x-service-token",
 	"x-storage-token", "x-account-meta-temp-url-key", "x-account-meta-temp-url-key-2",
 	"x-container-meta-temp-url-key", "x-container-meta-temp-url-key-2", "set-cookie",
-	"x-subject-token"}
+	"x-subject-token", "authorization", "x-security-token"}
 
 // RedactHeaders processes a headers object, returning a redacted list
 func RedactHeaders(headers http.Header) (processedHeaders []string) {
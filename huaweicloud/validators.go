@@ -72,3 +72,31 @@ func validateKmsKeyStatus(v interface{}, k string) (ws []string, errors []error)
 	}
 	return
 }
+
+func validateServiceEndpoints(v interface{}, k string) (ws []string, errors []error) {
+	for service := range v.(map[string]interface{}) {
+		if _, errs := ValidateStringList(service, k, serviceEndpoints); len(errs) > 0 {
+			errors = append(errors, fmt.Errorf(
+				"%q contains the unknown service %q, must be one of %v", k, service, serviceEndpoints))
+		}
+	}
+	return
+}
+
+func validateAssumeRoleDuration(v interface{}, k string) (ws []string, errors []error) {
+	value := v.(int)
+	if value < 900 || value > 86400 {
+		errors = append(errors, fmt.Errorf(
+			"%q must be between 900 and 86400 seconds", k))
+	}
+	return
+}
+
+func validateNonNegativeInt(v interface{}, k string) (ws []string, errors []error) {
+	value := v.(int)
+	if value < 0 {
+		errors = append(errors, fmt.Errorf(
+			"%q must not be negative", k))
+	}
+	return
+}
@@ -0,0 +1,271 @@
+package huaweicloud
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+const (
+	vcrModeRecord = "record"
+	vcrModeReplay = "replay"
+
+	defaultVCRDir = "testdata/cassettes"
+)
+
+// vcrInteraction is a request and its response, as recorded in a cassette.
+type vcrInteraction struct {
+	Method          string      `json:"method"`
+	URL             string      `json:"url"`
+	RequestHeaders  http.Header `json:"request_headers,omitempty"`
+	RequestBody     string      `json:"request_body,omitempty"`
+	StatusCode      int         `json:"status_code"`
+	ResponseHeaders http.Header `json:"response_headers,omitempty"`
+	ResponseBody    string      `json:"response_body,omitempty"`
+
+	used bool
+}
+
+// vcrCassette holds the interactions recorded for an acceptance test, along
+// with the environment variables the test ran with.
+type vcrCassette struct {
+	mu       sync.Mutex
+	path     string
+	replay   bool
+	redactor *Redactor
+
+	Env          map[string]string `json:"env,omitempty"`
+	Interactions []*vcrInteraction `json:"interactions"`
+}
+
+// vcrCassettes holds the cassettes opened by the process, so that the provider
+// configured again by every step of a test keeps using the same cassette.
+var vcrCassettes = struct {
+	sync.Mutex
+	m map[string]*vcrCassette
+}{m: make(map[string]*vcrCassette)}
+
+// openVCRCassette returns the cassette named name in HW_VCR_DIR. In replay
+// mode it is read from disk, in record mode it starts empty and replaces any
+// previous recording once written.
+func openVCRCassette(mode, name string) (*vcrCassette, error) {
+	if mode != vcrModeRecord && mode != vcrModeReplay {
+		return nil, fmt.Errorf("Invalid HW_VCR_MODE %q, expected %s or %s", mode, vcrModeRecord, vcrModeReplay)
+	}
+	if name == "" {
+		return nil, fmt.Errorf("HW_VCR_CASSETTE must be set in %s mode", mode)
+	}
+
+	dir := os.Getenv("HW_VCR_DIR")
+	if dir == "" {
+		dir = defaultVCRDir
+	}
+	path := filepath.Join(dir, strings.Replace(name, "/", "_", -1)+".json")
+
+	vcrCassettes.Lock()
+	defer vcrCassettes.Unlock()
+
+	if cassette, ok := vcrCassettes.m[path]; ok {
+		return cassette, nil
+	}
+
+	cassette := &vcrCassette{
+		path:     path,
+		replay:   mode == vcrModeReplay,
+		redactor: newRedactor(),
+	}
+	if cassette.replay {
+		raw, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("Error reading cassette: %s", err)
+		}
+		if err := json.Unmarshal(raw, cassette); err != nil {
+			return nil, fmt.Errorf("Error parsing cassette %s: %s", path, err)
+		}
+		log.Printf("[DEBUG] Replaying %d interactions from %s", len(cassette.Interactions), path)
+	} else {
+		log.Printf("[DEBUG] Recording interactions to %s", path)
+	}
+
+	vcrCassettes.m[path] = cassette
+	return cassette, nil
+}
+
+// loadVCRCassette opens the cassette of HW_VCR_CASSETTE if HW_VCR_MODE is set,
+// to record or replay the API requests of an acceptance test.
+func (c *Config) loadVCRCassette() error {
+	mode := os.Getenv("HW_VCR_MODE")
+	if mode == "" {
+		return nil
+	}
+
+	cassette, err := openVCRCassette(mode, os.Getenv("HW_VCR_CASSETTE"))
+	if err != nil {
+		return err
+	}
+	c.vcrCassette = cassette
+
+	return nil
+}
+
+// SetEnv saves the environment variables of a recorded test.
+func (v *vcrCassette) SetEnv(env map[string]string) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.Env = env
+	return v.save()
+}
+
+// record appends an interaction to the cassette, with sensitive values
+// redacted, and writes the cassette to disk.
+func (v *vcrCassette) record(request *http.Request, requestBody []byte, response *http.Response, responseBody []byte) error {
+	interaction := &vcrInteraction{
+		Method:          request.Method,
+		URL:             v.redactor.RedactURL(request.URL),
+		RequestHeaders:  v.redactHeaders(request.Header),
+		RequestBody:     v.redactBody(requestBody),
+		StatusCode:      response.StatusCode,
+		ResponseHeaders: v.redactHeaders(response.Header),
+		ResponseBody:    v.redactBody(responseBody),
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.Interactions = append(v.Interactions, interaction)
+	return v.save()
+}
+
+// next returns the first unused interaction for the request. Interactions with
+// the same method and URL are preferred, then those with the same path, as
+// query strings may contain random names.
+func (v *vcrCassette) next(request *http.Request) (*vcrInteraction, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	url := v.redactor.RedactURL(request.URL)
+	matches := []func(*vcrInteraction) bool{
+		func(i *vcrInteraction) bool { return i.URL == url },
+		func(i *vcrInteraction) bool {
+			return strings.SplitN(i.URL, "?", 2)[0] == strings.SplitN(url, "?", 2)[0]
+		},
+	}
+	for _, match := range matches {
+		for _, interaction := range v.Interactions {
+			if !interaction.used && interaction.Method == request.Method && match(interaction) {
+				interaction.used = true
+				return interaction, nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("No recorded interaction left in %s for %s %s", v.path, request.Method, url)
+}
+
+func (v *vcrCassette) save() error {
+	raw, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(v.path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(v.path, raw, 0644)
+}
+
+func (v *vcrCassette) redactHeaders(headers http.Header) http.Header {
+	redacted := make(http.Header, len(headers))
+	for name, values := range headers {
+		if v.redactor.Headers[strings.ToLower(name)] {
+			redacted[name] = []string{redactedValue}
+			continue
+		}
+		redacted[name] = values
+	}
+	return redacted
+}
+
+func (v *vcrCassette) redactBody(body []byte) string {
+	var data interface{}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return string(body)
+	}
+
+	redacted, err := json.Marshal(v.redactor.RedactJSON(data))
+	if err != nil {
+		return string(body)
+	}
+	return string(redacted)
+}
+
+// vcrRoundTripper satisfies the http.RoundTripper interface and records the
+// requests sent through Rt into a cassette, or replays them from the cassette
+// without sending them.
+type vcrRoundTripper struct {
+	Rt       http.RoundTripper
+	Cassette *vcrCassette
+}
+
+// RoundTrip records or replays a round-trip HTTP request.
+func (vrt *vcrRoundTripper) RoundTrip(request *http.Request) (*http.Response, error) {
+	var requestBody []byte
+	if request.Body != nil {
+		var err error
+		requestBody, err = ioutil.ReadAll(request.Body)
+		request.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if vrt.Cassette.replay {
+		interaction, err := vrt.Cassette.next(request)
+		if err != nil {
+			return nil, err
+		}
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.StatusCode, http.StatusText(interaction.StatusCode)),
+			StatusCode:    interaction.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        interaction.ResponseHeaders,
+			Body:          ioutil.NopCloser(strings.NewReader(interaction.ResponseBody)),
+			ContentLength: int64(len(interaction.ResponseBody)),
+			Request:       request,
+		}, nil
+	}
+
+	// Don't modify the caller's request, http.RoundTripper forbids it.
+	try := new(http.Request)
+	*try = *request
+	if requestBody != nil {
+		try.Body = ioutil.NopCloser(bytes.NewReader(requestBody))
+	}
+
+	response, err := vrt.Rt.RoundTrip(try)
+	if err != nil {
+		return nil, err
+	}
+
+	responseBody, err := ioutil.ReadAll(response.Body)
+	response.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	response.Body = ioutil.NopCloser(bytes.NewReader(responseBody))
+
+	if err := vrt.Cassette.record(request, requestBody, response, responseBody); err != nil {
+		return nil, fmt.Errorf("Error recording interaction: %s", err)
+	}
+
+	return response, nil
+}
@@ -0,0 +1,72 @@
+package huaweicloud
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestVCRRecordReplay(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cassettes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	os.Setenv("HW_VCR_DIR", dir)
+	defer os.Unsetenv("HW_VCR_DIR")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Subject-Token", "secret-token")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"server":{"id":"server-1","adminPass":"secret-pass"}}`))
+	}))
+
+	recorder, err := openVCRCassette(vcrModeRecord, "TestVCR/record")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &http.Client{Transport: &vcrRoundTripper{Rt: http.DefaultTransport, Cassette: recorder}}
+	resp, err := client.Post(server.URL+"/servers", "application/json", strings.NewReader(`{"server":{}}`))
+	if err != nil {
+		t.Fatalf("Error recording: %s", err)
+	}
+	resp.Body.Close()
+	server.Close()
+
+	raw, err := ioutil.ReadFile(recorder.path)
+	if err != nil {
+		t.Fatalf("Error reading cassette: %s", err)
+	}
+	if strings.Contains(string(raw), "secret-") {
+		t.Fatalf("Expected secrets to be redacted from the cassette:\n%s", raw)
+	}
+
+	// Replay from a fresh cassette, the server is gone.
+	vcrCassettes.Lock()
+	delete(vcrCassettes.m, recorder.path)
+	vcrCassettes.Unlock()
+
+	player, err := openVCRCassette(vcrModeReplay, "TestVCR/record")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.Transport = &vcrRoundTripper{Cassette: player}
+	resp, err = client.Post(server.URL+"/servers", "application/json", strings.NewReader(`{"server":{}}`))
+	if err != nil {
+		t.Fatalf("Error replaying: %s", err)
+	}
+	body, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated || !strings.Contains(string(body), `"server-1"`) {
+		t.Fatalf("Unexpected replayed response %d: %s", resp.StatusCode, body)
+	}
+
+	if _, err := client.Post(server.URL+"/servers", "application/json", nil); err == nil {
+		t.Fatal("Expected an error once the interactions are exhausted")
+	}
+}